@@ -0,0 +1,98 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+var (
+	ansiEscape = regexp.MustCompile("\x1b\\[([0-9;]*)([A-Za-z])")
+	// Jenkins embeds serialized console notes hidden behind the conceal code.
+	consoleNote = regexp.MustCompile("\x1b\\[8mha:[^\x1b]*\x1b\\[0m")
+)
+
+var ansiColors = map[int]fyne.ThemeColorName{
+	31: theme.ColorNameError,
+	32: theme.ColorNameSuccess,
+	33: theme.ColorNameWarning,
+	34: theme.ColorNamePrimary,
+	35: theme.ColorNameHyperlink,
+	36: theme.ColorNameHyperlink,
+	90: theme.ColorNamePlaceHolder,
+	91: theme.ColorNameError,
+	92: theme.ColorNameSuccess,
+	93: theme.ColorNameWarning,
+	94: theme.ColorNamePrimary,
+	95: theme.ColorNameHyperlink,
+	96: theme.ColorNameHyperlink,
+}
+
+type ansiState struct {
+	color fyne.ThemeColorName
+	bold  bool
+}
+
+func (s *ansiState) apply(params string) {
+	if params == "" {
+		params = "0"
+	}
+	for _, param := range strings.Split(params, ";") {
+		code, _ := strconv.Atoi(param)
+		switch {
+		case code == 0:
+			*s = ansiState{}
+		case code == 1:
+			s.bold = true
+		case code == 22:
+			s.bold = false
+		case code == 39:
+			s.color = ""
+		case code >= 30 && code <= 37, code >= 90 && code <= 97:
+			s.color = ansiColors[code]
+		}
+	}
+}
+
+func (s *ansiState) segment(text string) *widget.TextSegment {
+	return &widget.TextSegment{
+		Text: text,
+		Style: widget.RichTextStyle{
+			Inline:    true,
+			ColorName: s.color,
+			TextStyle: fyne.TextStyle{Monospace: true, Bold: s.bold},
+		},
+	}
+}
+
+// ansiSegments renders a single line containing ANSI escape codes as rich
+// text segments. The style carries over between lines through state.
+func ansiSegments(line string, state *ansiState) []widget.RichTextSegment {
+	line = consoleNote.ReplaceAllString(line, "")
+
+	var segments []widget.RichTextSegment
+	last := 0
+	for _, match := range ansiEscape.FindAllStringSubmatchIndex(line, -1) {
+		if match[0] > last {
+			segments = append(segments, state.segment(line[last:match[0]]))
+		}
+		if line[match[4]:match[5]] == "m" {
+			state.apply(line[match[2]:match[3]])
+		}
+		last = match[1]
+	}
+	if last < len(line) || len(segments) == 0 {
+		segments = append(segments, state.segment(line[last:]))
+	}
+
+	segments[len(segments)-1].(*widget.TextSegment).Style.Inline = false
+	return segments
+}
+
+func stripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(consoleNote.ReplaceAllString(s, ""), "")
+}
@@ -0,0 +1,525 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/storage/repository"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"webservices/jenkins"
+)
+
+// artifactRow is an artifact listed with the state of its download. Its
+// fields are only touched from the UI goroutine.
+type artifactRow struct {
+	artifact jenkins.Artifact
+	// uri is where the artifact is stored once downloaded, it is written to
+	// part until then. dirs are the folders holding it below the download
+	// folder of the build.
+	uri, part fyne.URI
+	dirs      []string
+	// size and partSize are the sizes of the files, -1 when missing.
+	size, partSize int64
+	// exported is the copy saved by the user, which other apps can be given
+	// access to.
+	exported fyne.URI
+	cancel   context.CancelFunc
+
+	name     *widget.Label
+	status   *widget.Label
+	progress *widget.ProgressBar
+	download *widget.Button
+	open     *widget.Button
+	share    *widget.Button
+	save     *widget.Button
+}
+
+// refresh shows the state of the download.
+func (r *artifactRow) refresh() {
+	r.open.Hide()
+	r.share.Hide()
+	r.save.Hide()
+	r.progress.Hide()
+
+	switch {
+	case r.cancel != nil:
+		r.download.SetIcon(theme.CancelIcon())
+		r.progress.Show()
+	case r.size >= 0:
+		r.download.SetIcon(theme.ViewRefreshIcon())
+		r.status.SetText("Downloaded, " + formatBytes(r.size))
+		r.open.Show()
+		if privateStorage {
+			r.share.Show()
+		}
+		r.save.Show()
+	case r.partSize >= 0:
+		r.download.SetIcon(theme.DownloadIcon())
+		r.status.SetText("Paused at " + formatBytes(r.partSize) + ", tap to resume")
+	default:
+		r.download.SetIcon(theme.DownloadIcon())
+		r.status.SetText(r.artifact.DisplayPath)
+	}
+}
+
+// mimeType guesses the type of the artifact from its name.
+func (r *artifactRow) mimeType() string {
+	if t := mime.TypeByExtension(path.Ext(r.artifact.FileName)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// artifactsViewer lists the artifacts of a build and downloads them to the
+// app storage.
+type artifactsViewer struct {
+	ctx    context.Context
+	app    fyne.App
+	window fyne.Window
+	client *jenkins.Client
+	build  jenkins.Build
+	// dir is the download folder of the build, named after the server and
+	// the build.
+	dir fyne.URI
+
+	rows   *fyne.Container
+	status *widget.Label
+}
+
+// showArtifacts opens the artifacts of a build in a new window. Closing the
+// window pauses the downloads, which resume when started again.
+func showArtifacts(ctx context.Context, a fyne.App, client *jenkins.Client, title string, build jenkins.Build) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	w := a.NewWindow("Artifacts: " + title)
+	w.SetOnClosed(cancel)
+
+	v := &artifactsViewer{
+		ctx:    ctx,
+		app:    a,
+		window: w,
+		client: client,
+		build:  build,
+		rows:   container.NewVBox(),
+		status: widget.NewLabel("Loading artifacts..."),
+	}
+
+	w.SetContent(container.NewBorder(nil, v.status, nil, nil, container.NewVScroll(v.rows)))
+	w.Resize(fyne.NewSize(600, 500))
+	w.Show()
+
+	go func() {
+		dir, err := downloadDir(a, client, build.URL)
+		if err != nil {
+			fyne.Do(func() { v.status.SetText("Error locating downloads: " + err.Error()) })
+			return
+		}
+		v.dir = dir
+
+		artifacts, err := client.Artifacts(ctx, build.URL)
+		if err != nil {
+			fyne.Do(func() { v.status.SetText("Error fetching artifacts: " + err.Error()) })
+			return
+		}
+		rows := make([]*artifactRow, 0, len(artifacts))
+		for _, artifact := range artifacts {
+			row, err := v.newRow(artifact)
+			if err != nil {
+				fmt.Println("Error locating " + artifact.RelativePath + ": " + err.Error())
+				continue
+			}
+			rows = append(rows, row)
+		}
+
+		fyne.Do(func() {
+			for _, row := range rows {
+				v.addRow(row)
+			}
+			v.status.SetText(fmt.Sprintf("%d artifacts", len(rows)))
+			if len(artifacts) == 0 {
+				v.status.SetText("This build archived no artifacts")
+			}
+		})
+	}()
+}
+
+// downloadDir returns the folder of the app storage the artifacts of the
+// build are downloaded to, creating it. The folders follow the host and the
+// path of the build, so that builds of different jobs never share one.
+func downloadDir(a fyne.App, client *jenkins.Client, buildURL string) (fyne.URI, error) {
+	if a.Storage() == nil || a.Storage().RootURI() == nil {
+		return nil, errors.New("the app has no storage")
+	}
+	host := client.BaseURL
+	if u, err := url.Parse(client.BaseURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	build, err := url.Parse(buildURL)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{"downloads", escapeFileName(host)}
+	for _, name := range strings.Split(build.Path, "/") {
+		if name != "" {
+			names = append(names, escapeFileName(name))
+		}
+	}
+	return storageDir(a.Storage().RootURI(), names...)
+}
+
+// storageDir returns the folder at the path made of names below root,
+// creating the missing folders.
+func storageDir(root fyne.URI, names ...string) (fyne.URI, error) {
+	dir := root
+	for _, name := range names {
+		child, err := storage.Child(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		exists, err := storage.Exists(child)
+		if err == nil && !exists {
+			err = storage.CreateListable(child)
+		}
+		if err != nil {
+			return nil, err
+		}
+		dir = child
+	}
+	return dir, nil
+}
+
+// escapeFileName turns name into a file name, escaping the bytes which are
+// unsafe in file names like in URLs so that different names never collide.
+func escapeFileName(name string) string {
+	var escaped strings.Builder
+	for _, c := range []byte(name) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '-', c == '_',
+			c == '.' && strings.Trim(name, ".") != "":
+			escaped.WriteByte(c)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", c)
+		}
+	}
+	return escaped.String()
+}
+
+// newRow locates the files of the artifact, keeping the folders it was
+// archived in.
+func (v *artifactsViewer) newRow(artifact jenkins.Artifact) (*artifactRow, error) {
+	names := []string{escapeFileName(artifact.FileName)}
+	if filepath.IsLocal(filepath.FromSlash(artifact.RelativePath)) {
+		names = strings.Split(artifact.RelativePath, "/")
+		for i, name := range names {
+			names[i] = escapeFileName(name)
+		}
+	}
+	row := &artifactRow{artifact: artifact, dirs: names[:len(names)-1]}
+
+	dir := v.dir
+	var err error
+	for _, name := range row.dirs {
+		if dir, err = storage.Child(dir, name); err != nil {
+			return nil, err
+		}
+	}
+	file := names[len(names)-1]
+	if row.uri, err = storage.Child(dir, file); err != nil {
+		return nil, err
+	}
+	if row.part, err = storage.Child(dir, file+".part"); err != nil {
+		return nil, err
+	}
+	row.size = uriSize(row.uri)
+	row.partSize = uriSize(row.part)
+	return row, nil
+}
+
+func (v *artifactsViewer) addRow(row *artifactRow) {
+	row.name = widget.NewLabel(row.artifact.FileName)
+	row.status = widget.NewLabel("")
+	row.progress = widget.NewProgressBar()
+	row.name.TextStyle.Bold = true
+	row.status.SizeName = theme.SizeNameCaptionText
+	row.status.Truncation = fyne.TextTruncateEllipsis
+
+	row.download = widget.NewButtonWithIcon("", theme.DownloadIcon(), func() {
+		if row.cancel != nil {
+			row.cancel()
+			return
+		}
+		v.start(row)
+	})
+	row.open = widget.NewButtonWithIcon("Open", theme.FileApplicationIcon(), func() {
+		v.withReadableURI(row, func(u fyne.URI) error {
+			return openURI(v.app, u, row.mimeType())
+		})
+	})
+	row.share = widget.NewButtonWithIcon("Share", theme.MailSendIcon(), func() {
+		v.withReadableURI(row, func(u fyne.URI) error {
+			return shareURI(u, row.mimeType())
+		})
+	})
+	row.save = widget.NewButtonWithIcon("Save as", theme.DocumentSaveIcon(), func() {
+		v.saveAs(row, nil)
+	})
+
+	row.refresh()
+	v.rows.Add(container.NewVBox(
+		container.NewBorder(nil, nil, nil,
+			container.NewHBox(row.open, row.share, row.save, row.download),
+			container.NewVBox(row.name, row.status),
+		),
+		row.progress,
+		widget.NewSeparator(),
+	))
+}
+
+// start downloads the artifact in the background, resuming a download which
+// was interrupted, or starting over when it was downloaded already.
+func (v *artifactsViewer) start(row *artifactRow) {
+	ctx, cancel := context.WithCancel(v.ctx)
+	row.cancel = cancel
+	row.status.SetText("Starting download...")
+	row.progress.SetValue(0)
+	row.refresh()
+
+	artifactURL := row.artifact.URL(v.build.URL)
+	redownload := row.size >= 0
+
+	go func() {
+		var err error
+		if redownload {
+			err = storage.Delete(row.uri)
+		}
+		if err == nil {
+			_, err = storageDir(v.dir, row.dirs...)
+		}
+		// The file tells where to resume, the progress shown lagging behind
+		// what was written.
+		offset := max(uriSize(row.part), 0)
+		var size int64
+		if err == nil {
+			size, err = v.download(ctx, artifactURL, row.part, offset, func(done, size int64) {
+				fyne.Do(func() {
+					row.partSize = done
+					if size > 0 {
+						row.progress.SetValue(float64(done) / float64(size))
+						row.status.SetText(formatBytes(done) + " of " + formatBytes(size))
+					} else {
+						row.status.SetText(formatBytes(done))
+					}
+				})
+			})
+		}
+		if err == nil {
+			err = moveURI(row.part, row.uri)
+		}
+		partSize := uriSize(row.part)
+
+		fyne.Do(func() {
+			cancel()
+			row.cancel = nil
+			if redownload {
+				row.size = -1
+			}
+			row.partSize = partSize
+			if err == nil {
+				row.size = size
+			}
+			row.refresh()
+			switch {
+			case errors.Is(err, context.Canceled):
+			case err != nil:
+				row.status.SetText("Download failed: " + err.Error())
+			default:
+				v.app.SendNotification(&fyne.Notification{
+					Title:   "Downloaded " + row.artifact.FileName,
+					Content: formatBytes(size),
+				})
+			}
+		})
+	}()
+}
+
+// download appends the artifact to the file at part from offset, calling
+// progress at most a few times per second. It returns the size of the file.
+func (v *artifactsViewer) download(ctx context.Context, artifactURL string, part fyne.URI, offset int64, progress func(done, size int64)) (int64, error) {
+	download, err := v.client.DownloadArtifact(ctx, artifactURL, offset)
+	if err != nil {
+		return 0, err
+	}
+	defer download.Body.Close()
+
+	var file fyne.URIWriteCloser
+	if download.Offset > 0 {
+		file, err = storage.Appender(part)
+	} else {
+		file, err = storage.Writer(part)
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	done := download.Offset
+	lastReport := time.Time{}
+	buffer := make([]byte, 64*1024)
+	for {
+		n, readErr := download.Body.Read(buffer)
+		if n > 0 {
+			if _, err := file.Write(buffer[:n]); err != nil {
+				return 0, err
+			}
+			done += int64(n)
+			if time.Since(lastReport) > time.Second/5 {
+				lastReport = time.Now()
+				progress(done, download.Size)
+			}
+		}
+		if readErr == io.EOF {
+			progress(done, download.Size)
+			return done, file.Close()
+		}
+		if readErr != nil {
+			return 0, readErr
+		}
+	}
+}
+
+// withReadableURI calls fn with a URI of the downloaded artifact which other
+// apps can read. When the app storage is private, the user is asked where to
+// export the artifact to first.
+func (v *artifactsViewer) withReadableURI(row *artifactRow, fn func(fyne.URI) error) {
+	show := func(u fyne.URI) {
+		if err := fn(u); err != nil {
+			dialog.ShowError(err, v.window)
+		}
+	}
+	switch {
+	case !privateStorage:
+		show(row.uri)
+	case row.exported != nil:
+		if exists, _ := storage.Exists(row.exported); exists {
+			show(row.exported)
+			return
+		}
+		row.exported = nil
+		v.saveAs(row, show)
+	default:
+		v.saveAs(row, show)
+	}
+}
+
+// saveAs copies a downloaded artifact to a place picked by the user, then
+// calls saved with its URI unless it is nil.
+func (v *artifactsViewer) saveAs(row *artifactRow, saved func(fyne.URI)) {
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, v.window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+
+		row.status.SetText("Saving...")
+		go func() {
+			err := copyURI(row.uri, writer)
+			fyne.Do(func() {
+				if err != nil {
+					row.status.SetText("Saving failed: " + err.Error())
+					return
+				}
+				row.exported = writer.URI()
+				row.status.SetText("Saved to " + writer.URI().Name())
+				if saved != nil {
+					saved(writer.URI())
+				}
+			})
+		}()
+	}, v.window)
+	save.SetFileName(row.artifact.FileName)
+	save.Show()
+}
+
+// copyURI copies the file at source to writer, closing it.
+func copyURI(source fyne.URI, writer fyne.URIWriteCloser) error {
+	reader, err := storage.Reader(source)
+	if err != nil {
+		writer.Close()
+		return err
+	}
+	defer reader.Close()
+
+	if _, err := io.Copy(writer, reader); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// moveURI renames the file at source, copying it where the repository of
+// the storage cannot rename files.
+func moveURI(source, destination fyne.URI) error {
+	err := storage.Move(source, destination)
+	if errors.Is(err, repository.ErrOperationNotSupported) {
+		return repository.GenericMove(source, destination)
+	}
+	return err
+}
+
+// uriSize returns the size of the file at u, -1 when it does not exist.
+func uriSize(u fyne.URI) int64 {
+	if exists, err := storage.Exists(u); err != nil || !exists {
+		return -1
+	}
+	reader, err := storage.Reader(u)
+	if err != nil {
+		return -1
+	}
+	defer reader.Close()
+
+	// Local files tell their size, other readers are counted.
+	if file, ok := reader.(interface{ Stat() (fs.FileInfo, error) }); ok {
+		if info, err := file.Stat(); err == nil {
+			return info.Size()
+		}
+	}
+	size, err := io.Copy(io.Discard, reader)
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, suffix := float64(n)/unit, "KB"
+	for _, next := range []string{"MB", "GB", "TB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, next
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+
+	"webservices/jenkins"
+)
+
+func TestDownloadDir(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	a := test.NewTempApp(t)
+
+	client := jenkins.NewClient("https://jenkins.example.com:8443/ci", "", "")
+	dir, err := downloadDir(a, client, "https://jenkins.example.com:8443/ci/job/web/job/main%20line/5/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(dir.Path(), "/downloads/jenkins.example.com%3A8443/ci/job/web/job/main%20line/5") {
+		t.Errorf("downloadDir = %s, want it named after the host and the build", dir.Path())
+	}
+	if listable, err := storage.CanList(dir); err != nil || !listable {
+		t.Errorf("%s was not created: %v", dir, err)
+	}
+
+	// Same-named jobs of different folders keep their builds apart.
+	other, err := downloadDir(a, client, "https://jenkins.example.com:8443/ci/job/api/job/main%20line/5/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Path() == dir.Path() {
+		t.Errorf("the builds of two jobs share %s", dir.Path())
+	}
+}
+
+func TestEscapeFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"app-release_1.2.apk", "app-release_1.2.apk"},
+		{"main 5", "main%205"},
+		{"main_5", "main_5"},
+		{"feature/login", "feature%2Flogin"},
+		{"100%", "100%25"},
+		{"jenkins:8443", "jenkins%3A8443"},
+		{".", "%2E"},
+		{"..", "%2E%2E"},
+		{".hidden", ".hidden"},
+	}
+	for _, test := range tests {
+		if got := escapeFileName(test.name); got != test.want {
+			t.Errorf("escapeFileName(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+// uiApp runs the calls of fyne.Do on the test goroutine, like the driver
+// runs them on the UI goroutine.
+type uiApp struct {
+	fyne.App
+	calls chan func()
+}
+
+type uiDriver struct {
+	fyne.Driver
+	calls chan func()
+}
+
+func newUIApp(t *testing.T) *uiApp {
+	a := &uiApp{App: test.NewTempApp(t), calls: make(chan func(), 64)}
+	fyne.SetCurrentApp(a)
+	return a
+}
+
+func (a *uiApp) Driver() fyne.Driver {
+	return uiDriver{Driver: a.App.Driver(), calls: a.calls}
+}
+
+func (d uiDriver) DoFromGoroutine(fn func(), wait bool) {
+	done := make(chan struct{})
+	d.calls <- func() {
+		fn()
+		close(done)
+	}
+	if wait {
+		<-done
+	}
+}
+
+// runUntil runs the calls of fyne.Do until done returns true.
+func (a *uiApp) runUntil(t *testing.T, what string, done func() bool) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for !done() {
+		select {
+		case call := <-a.calls:
+			call()
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("timed out waiting for " + what)
+		}
+	}
+}
+
+func TestPauseAndResume(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	a := newUIApp(t)
+
+	content := bytes.Repeat([]byte("0123456789"), 20000)
+	ranges := make(chan string, 2)
+	rest := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges <- r.Header.Get("Range")
+		if r.Header.Get("Range") != "" {
+			http.ServeContent(w, r, "app.apk", time.Time{}, bytes.NewReader(content))
+			return
+		}
+		// Send two chunks, the second one too soon to be reported, then
+		// stall until the download is paused.
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Write(content[:1000])
+		w.(http.Flusher).Flush()
+		<-rest
+		w.Write(content[1000:1500])
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := jenkins.NewClient(server.URL, "", "")
+	build := jenkins.Build{URL: server.URL + "/job/app/12/"}
+	dir, err := downloadDir(a, client, build.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &artifactsViewer{ctx: context.Background(), app: a, client: client, build: build, dir: dir, rows: container.NewVBox()}
+	row, err := v.newRow(jenkins.Artifact{FileName: "app.apk", RelativePath: "out/app.apk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.addRow(row)
+
+	test.Tap(row.download)
+	a.runUntil(t, "the first chunk", func() bool { return row.partSize == 1000 })
+	close(rest)
+	a.runUntil(t, "the second chunk", func() bool { return uriSize(row.part) == 1500 })
+	test.Tap(row.download)
+	a.runUntil(t, "the pause", func() bool { return row.cancel == nil })
+	if row.partSize != 1500 || row.size != -1 {
+		t.Errorf("paused with %d bytes and size %d, want 1500 bytes", row.partSize, row.size)
+	}
+
+	test.Tap(row.download)
+	a.runUntil(t, "the download", func() bool { return row.cancel == nil && row.size >= 0 })
+	if got := []string{<-ranges, <-ranges}; got[1] != "bytes=1500-" {
+		t.Errorf("sent the ranges %q, want to resume at 1500", got)
+	}
+	if row.size != int64(len(content)) || row.partSize != -1 {
+		t.Errorf("downloaded %d bytes, %d left in the part, want %d", row.size, row.partSize, len(content))
+	}
+
+	reader, err := storage.Reader(row.uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	if got, _ := io.ReadAll(reader); !bytes.Equal(got, content) {
+		t.Error("the resumed download differs from the artifact")
+	}
+}
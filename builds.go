@@ -0,0 +1,403 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"webservices/jenkins"
+)
+
+type buildAction struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// activeBuild is a build launched from the app which has not finished yet.
+type activeBuild struct {
+	queue *jenkins.QueueItem
+	build *jenkins.Build
+}
+
+// actions lists what can be done to stop the build, the least forceful first.
+func (b *activeBuild) actions(client *jenkins.Client) []buildAction {
+	if b.build != nil {
+		buildURL := b.build.URL
+		return []buildAction{
+			{"Abort build", func(ctx context.Context) error { return client.Stop(ctx, buildURL) }},
+			{"Terminate build", func(ctx context.Context) error { return client.Term(ctx, buildURL) }},
+			{"Kill build", func(ctx context.Context) error { return client.Kill(ctx, buildURL) }},
+		}
+	}
+	if b.queue != nil {
+		id := b.queue.ID
+		return []buildAction{
+			{"Cancel queued build", func(ctx context.Context) error { return client.CancelQueueItem(ctx, id) }},
+		}
+	}
+	return nil
+}
+
+// buildRow is a single build shown in the builds panel. Its fields are only
+// touched from the UI goroutine.
+type buildRow struct {
+	jobName string
+	jobURL  string
+	client  *jenkins.Client
+	current activeBuild
+	done    bool
+	err     error
+
+	title       *widget.Label
+	status      *widget.Label
+	progress    *widget.ProgressBar
+	logButton   *widget.Button
+	testsButton *widget.Button
+	filesButton *widget.Button
+	stopButton  *widget.Button
+	object      fyne.CanvasObject
+}
+
+func (r *buildRow) running() bool {
+	return !r.done && r.err == nil
+}
+
+func (r *buildRow) refresh() {
+	build := r.current.build
+	if build != nil {
+		r.title.SetText(fmt.Sprintf("%s #%d", r.jobName, build.Number))
+	} else {
+		r.title.SetText(r.jobName)
+	}
+
+	switch {
+	case r.err != nil:
+		r.status.SetText("Error: " + r.err.Error())
+	case r.done && build == nil:
+		r.status.SetText("Cancelled while in queue")
+	case r.done:
+		r.status.SetText(fmt.Sprintf("%s in %s", build.Result, build.Elapsed().Round(time.Second)))
+		r.progress.SetValue(1)
+	case build != nil:
+		elapsed := build.Elapsed().Round(time.Second)
+		estimated := build.Estimated().Round(time.Second)
+		if estimated > 0 {
+			r.status.SetText(fmt.Sprintf("Running for %s, estimated %s", elapsed, estimated))
+			r.progress.SetValue(min(float64(elapsed)/float64(estimated), 0.99))
+		} else {
+			r.status.SetText(fmt.Sprintf("Running for %s", elapsed))
+		}
+	case r.current.queue != nil:
+		r.status.SetText(r.current.queue.Status())
+	default:
+		r.status.SetText("Launching...")
+	}
+
+	if build != nil {
+		r.logButton.Show()
+	} else {
+		r.logButton.Hide()
+	}
+	if r.done && build != nil {
+		r.testsButton.Show()
+		r.filesButton.Show()
+	} else {
+		r.testsButton.Hide()
+		r.filesButton.Hide()
+	}
+	if r.running() {
+		r.stopButton.Show()
+	} else {
+		r.stopButton.Hide()
+	}
+}
+
+// buildsPanel tracks every build launched from the app, and optionally every
+// build running on the server, each one monitored on its own.
+type buildsPanel struct {
+	ctx       context.Context
+	app       fyne.App
+	window    fyne.Window
+	clients   func() []*jenkins.Client
+	runAction func(action buildAction)
+
+	rows    []*buildRow
+	rowsBox *fyne.Container
+	showAll *widget.Check
+	content fyne.CanvasObject
+
+	// scanning is held while looking for running builds on the servers, so
+	// that scans started by the check box and the ticker do not overlap.
+	scanning sync.Mutex
+}
+
+// newBuildsPanel creates the panel, clients returns the servers scanned when
+// showing all running builds and is called from the UI goroutine.
+func newBuildsPanel(ctx context.Context, a fyne.App, w fyne.Window, clients func() []*jenkins.Client, runAction func(action buildAction)) *buildsPanel {
+	p := &buildsPanel{
+		ctx:       ctx,
+		app:       a,
+		window:    w,
+		clients:   clients,
+		runAction: runAction,
+		rowsBox:   container.NewVBox(),
+	}
+
+	p.showAll = widget.NewCheck("Show all running builds", func(checked bool) {
+		if checked {
+			go p.scanServer()
+		}
+	})
+	clear := widget.NewButtonWithIcon("Clear finished", theme.ContentClearIcon(), p.clearFinished)
+
+	p.content = container.NewBorder(
+		container.NewHBox(p.showAll, clear), nil, nil, nil,
+		container.NewVScroll(p.rowsBox),
+	)
+
+	go p.tick()
+	return p
+}
+
+func (p *buildsPanel) Content() fyne.CanvasObject {
+	return p.content
+}
+
+// Running returns the builds of a job which are still queued or running.
+func (p *buildsPanel) Running(jobURL string) []*buildRow {
+	var rows []*buildRow
+	for _, row := range p.rows {
+		if row.jobURL == jobURL && row.running() {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// find returns the row of a build, which may still be queued when the build
+// it started is already known.
+func (p *buildsPanel) find(buildURL string) *buildRow {
+	for _, row := range p.rows {
+		current := row.current
+		if current.build != nil && current.build.URL == buildURL {
+			return row
+		}
+		if current.build == nil && current.queue != nil && current.queue.Executable != nil && current.queue.Executable.URL == buildURL {
+			return row
+		}
+	}
+	return nil
+}
+
+// launching tells if a build of the job was launched from the app and which
+// build it started is not known yet.
+func (p *buildsPanel) launching(jobURL string) bool {
+	for _, row := range p.rows {
+		if row.jobURL == jobURL && row.running() && row.current.build == nil &&
+			(row.current.queue == nil || row.current.queue.Executable == nil) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *buildsPanel) addRow(client *jenkins.Client, jobName, jobURL string) *buildRow {
+	row := &buildRow{
+		jobName:  jobName,
+		jobURL:   jobURL,
+		client:   client,
+		title:    widget.NewLabel(jobName),
+		status:   widget.NewLabel(""),
+		progress: widget.NewProgressBar(),
+	}
+	row.title.TextStyle.Bold = true
+	row.logButton = widget.NewButtonWithIcon("", theme.DocumentIcon(), func() {
+		build := row.current.build
+		showLogViewer(p.ctx, p.app, row.client, fmt.Sprintf("%s #%d", row.jobName, build.Number), build.URL)
+	})
+	row.testsButton = widget.NewButtonWithIcon("", theme.ListIcon(), func() {
+		build := row.current.build
+		showTestReport(p.ctx, p.app, row.client, fmt.Sprintf("%s #%d", row.jobName, build.Number), *build)
+	})
+	row.filesButton = widget.NewButtonWithIcon("", theme.DownloadIcon(), func() {
+		build := row.current.build
+		showArtifacts(p.ctx, p.app, row.client, fmt.Sprintf("%s #%d", row.jobName, build.Number), *build)
+	})
+	row.stopButton = widget.NewButtonWithIcon("", theme.MediaStopIcon(), func() {
+		menu := fyne.NewMenu("")
+		for _, action := range row.current.actions(row.client) {
+			menu.Items = append(menu.Items, fyne.NewMenuItem(action.Name, func() { p.runAction(action) }))
+		}
+		widget.ShowPopUpMenuAtRelativePosition(menu, p.window.Canvas(), fyne.NewPos(0, row.stopButton.Size().Height), row.stopButton)
+	})
+	row.object = container.NewVBox(
+		container.NewBorder(nil, nil, nil, container.NewHBox(row.logButton, row.testsButton, row.filesButton, row.stopButton), row.title),
+		row.progress,
+		row.status,
+		widget.NewSeparator(),
+	)
+
+	row.refresh()
+	p.rows = append(p.rows, row)
+	p.rowsBox.Add(row.object)
+	return row
+}
+
+// Launch adds a row for the job and follows the build queued by request until
+// it finishes. It must be called from the UI goroutine.
+func (p *buildsPanel) Launch(client *jenkins.Client, job jenkins.Job, request func() (string, error)) {
+	row := p.addRow(client, job.Name, job.URL)
+
+	go func() {
+		queueURL, err := request()
+		if err != nil {
+			fyne.Do(func() {
+				row.err = err
+				row.refresh()
+			})
+			return
+		}
+
+		p.app.SendNotification(&fyne.Notification{
+			Title:   "Job launched: " + job.Name,
+			Content: "Waiting for job to finish...",
+		})
+
+		build, err := client.Follow(p.ctx, queueURL, time.Second*2, func(progress jenkins.Progress) {
+			fyne.Do(func() {
+				if progress.Queue != nil {
+					row.current.queue = progress.Queue
+				} else {
+					row.current.build = progress.Build
+				}
+				row.refresh()
+			})
+		})
+		p.finish(row, build, err)
+	}()
+}
+
+// watch follows a build which was not launched from the app.
+func (p *buildsPanel) watch(client *jenkins.Client, build jenkins.Build) {
+	name := build.FullDisplayName
+	if suffix := fmt.Sprintf(" #%d", build.Number); len(name) > len(suffix) {
+		name = name[:len(name)-len(suffix)]
+	}
+
+	row := p.addRow(client, name, build.JobURL())
+	row.current.build = &build
+	row.refresh()
+
+	go func() {
+		finished, err := client.WatchBuild(p.ctx, build.URL, time.Second*2, func(build *jenkins.Build) {
+			fyne.Do(func() {
+				row.current.build = build
+				row.refresh()
+			})
+		})
+		p.finish(row, finished, err)
+	}()
+}
+
+func (p *buildsPanel) finish(row *buildRow, build *jenkins.Build, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	fyne.Do(func() {
+		switch {
+		case errors.Is(err, jenkins.ErrQueueItemCancelled):
+			row.done = true
+		case err != nil:
+			row.err = err
+		default:
+			row.done = true
+			row.current.build = build
+		}
+		row.refresh()
+	})
+
+	if build != nil {
+		p.app.SendNotification(&fyne.Notification{
+			Title:   fmt.Sprintf("%s #%d finished with %s", row.jobName, build.Number, build.Result),
+			Content: build.URL + "console",
+		})
+	}
+}
+
+func (p *buildsPanel) clearFinished() {
+	var rows []*buildRow
+	for _, row := range p.rows {
+		if row.running() {
+			rows = append(rows, row)
+		} else {
+			p.rowsBox.Remove(row.object)
+		}
+	}
+	p.rows = rows
+}
+
+// scanServer adds a row for the running builds which are not shown yet. Builds
+// of jobs just launched from the app are left to the next scan, as they may be
+// the launched build itself.
+func (p *buildsPanel) scanServer() {
+	if !p.scanning.TryLock() {
+		return
+	}
+	defer p.scanning.Unlock()
+
+	var clients []*jenkins.Client
+	fyne.DoAndWait(func() { clients = p.clients() })
+
+	for _, client := range clients {
+		builds, err := client.RunningBuilds(p.ctx)
+		if err != nil {
+			fmt.Println("Error fetching running builds: " + err.Error())
+			continue
+		}
+
+		fyne.Do(func() {
+			for _, build := range builds {
+				if p.find(build.URL) == nil && !p.launching(build.JobURL()) {
+					p.watch(client, build)
+				}
+			}
+		})
+	}
+}
+
+// tick keeps elapsed times up to date and periodically looks for new builds
+// on the server when all running builds are shown.
+func (p *buildsPanel) tick() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for i := 1; ; i++ {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		showAll := false
+		fyne.DoAndWait(func() {
+			for _, row := range p.rows {
+				if row.running() {
+					row.refresh()
+				}
+			}
+			showAll = p.showAll.Checked
+		})
+
+		if i%10 == 0 && showAll {
+			p.scanServer()
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"image/color"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// barChart plots one bar per value, drawn with canvas primitives. When
+// format is set, the maximum and the average are labelled.
+type barChart struct {
+	widget.BaseWidget
+	values []float64
+	colors []fyne.ThemeColorName
+	format func(float64) string
+}
+
+func newBarChart(values []float64, colors []fyne.ThemeColorName, format func(float64) string) *barChart {
+	c := &barChart{values: values, colors: colors, format: format}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+func (c *barChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &barChartRenderer{
+		chart:    c,
+		axis:     canvas.NewLine(color.Transparent),
+		average:  canvas.NewLine(color.Transparent),
+		maxLabel: canvas.NewText("", color.Transparent),
+		avgLabel: canvas.NewText("", color.Transparent),
+	}
+	for range c.values {
+		r.bars = append(r.bars, canvas.NewRectangle(color.Transparent))
+	}
+	r.maxLabel.TextSize = theme.CaptionTextSize()
+	r.avgLabel.TextSize = theme.CaptionTextSize()
+	r.average.StrokeWidth = 1
+	r.axis.StrokeWidth = 1
+	r.applyTheme()
+	return r
+}
+
+type barChartRenderer struct {
+	chart    *barChart
+	bars     []*canvas.Rectangle
+	axis     *canvas.Line
+	average  *canvas.Line
+	maxLabel *canvas.Text
+	avgLabel *canvas.Text
+}
+
+func (r *barChartRenderer) stats() (maximum, average float64) {
+	if len(r.chart.values) == 0 {
+		return 0, 0
+	}
+	sum := 0.0
+	for _, value := range r.chart.values {
+		sum += value
+	}
+	return slices.Max(r.chart.values), sum / float64(len(r.chart.values))
+}
+
+func (r *barChartRenderer) applyTheme() {
+	for i, bar := range r.bars {
+		bar.FillColor = theme.Color(r.chart.colors[i])
+	}
+	r.axis.StrokeColor = theme.Color(theme.ColorNameForeground)
+	r.average.StrokeColor = theme.Color(theme.ColorNamePlaceHolder)
+	r.maxLabel.Color = theme.Color(theme.ColorNamePlaceHolder)
+	r.avgLabel.Color = theme.Color(theme.ColorNamePlaceHolder)
+
+	maximum, average := r.stats()
+	if r.chart.format != nil && len(r.chart.values) > 0 {
+		r.maxLabel.Text = "max " + r.chart.format(maximum)
+		r.avgLabel.Text = "avg " + r.chart.format(average)
+	}
+}
+
+func (r *barChartRenderer) Layout(size fyne.Size) {
+	top := float32(0)
+	if r.chart.format != nil {
+		top = r.maxLabel.MinSize().Height
+	}
+	height := size.Height - top
+
+	r.maxLabel.Move(fyne.NewPos(0, 0))
+	r.axis.Position1 = fyne.NewPos(0, size.Height)
+	r.axis.Position2 = fyne.NewPos(size.Width, size.Height)
+
+	maximum, average := r.stats()
+	if len(r.bars) == 0 || maximum <= 0 {
+		for _, bar := range r.bars {
+			bar.Resize(fyne.NewSize(0, 0))
+		}
+		r.average.Hidden, r.avgLabel.Hidden = true, true
+		return
+	}
+
+	slot := size.Width / float32(len(r.bars))
+	gap := min(slot/4, 2)
+	for i, bar := range r.bars {
+		barHeight := height * float32(r.chart.values[i]/maximum)
+		bar.Move(fyne.NewPos(float32(i)*slot+gap/2, top+height-barHeight))
+		bar.Resize(fyne.NewSize(slot-gap, barHeight))
+	}
+
+	y := top + height*float32(1-average/maximum)
+	r.average.Position1 = fyne.NewPos(0, y)
+	r.average.Position2 = fyne.NewPos(size.Width, y)
+	labelSize := r.avgLabel.MinSize()
+	r.avgLabel.Move(fyne.NewPos(size.Width-labelSize.Width, max(y-labelSize.Height, 0)))
+	r.average.Hidden = r.chart.format == nil
+	r.avgLabel.Hidden = r.chart.format == nil
+}
+
+func (r *barChartRenderer) MinSize() fyne.Size {
+	if r.chart.format == nil {
+		return fyne.NewSize(100, theme.IconInlineSize())
+	}
+	return fyne.NewSize(100, 120)
+}
+
+func (r *barChartRenderer) Refresh() {
+	r.applyTheme()
+	r.Layout(r.chart.Size())
+	for _, object := range r.Objects() {
+		object.Refresh()
+	}
+}
+
+func (r *barChartRenderer) Objects() []fyne.CanvasObject {
+	objects := make([]fyne.CanvasObject, 0, len(r.bars)+4)
+	for _, bar := range r.bars {
+		objects = append(objects, bar)
+	}
+	return append(objects, r.axis, r.average, r.maxLabel, r.avgLabel)
+}
+
+func (r *barChartRenderer) Destroy() {}
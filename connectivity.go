@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// connectivity shows in the action bar whether the servers in use answer.
+type connectivity struct {
+	icon    *widget.Icon
+	label   *widget.Label
+	content fyne.CanvasObject
+}
+
+func newConnectivity() *connectivity {
+	c := &connectivity{
+		icon:  widget.NewIcon(nil),
+		label: widget.NewLabel(""),
+	}
+	c.content = container.NewHBox(c.icon, c.label)
+	c.update(nil)
+	return c
+}
+
+// update lists the profiles whose server did not answer the last request.
+func (c *connectivity) update(offline []string) {
+	if len(offline) == 0 {
+		c.icon.SetResource(theme.NewSuccessThemedResource(theme.ConfirmIcon()))
+		c.label.SetText("Online")
+		return
+	}
+	c.icon.SetResource(theme.NewErrorThemedResource(theme.ErrorIcon()))
+	c.label.SetText("Offline: " + strings.Join(offline, ", "))
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+var (
+	starIcon = theme.NewWarningThemedResource(fyne.NewStaticResource("star.svg", []byte(
+		`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path fill="#000000" d="M12 17.27 18.18 21l-1.64-7.03L22 9.24l-7.19-.61L12 2 9.19 8.63 2 9.24l5.46 4.73L5.82 21z"/></svg>`,
+	)))
+	starOutlineIcon = theme.NewThemedResource(fyne.NewStaticResource("star_outline.svg", []byte(
+		`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path fill="#000000" d="M22 9.24l-7.19-.62L12 2 9.19 8.63 2 9.24l5.46 4.73L5.82 21 12 17.27 18.18 21l-1.63-7.03L22 9.24zM12 15.4l-3.76 2.27 1-4.28-3.32-2.88 4.38-.38L12 6.1l1.71 4.04 4.38.38-3.32 2.88 1 4.28L12 15.4z"/></svg>`,
+	)))
+)
+
+func favoritesKey(profile string) string {
+	return "favorites:" + profile
+}
+
+// favorites are the job URLs starred by the user, stored per profile.
+type favorites struct {
+	prefs fyne.Preferences
+	urls  map[string][]string
+}
+
+func loadFavorites(prefs fyne.Preferences, profiles []string) *favorites {
+	f := &favorites{prefs: prefs, urls: map[string][]string{}}
+	for _, profile := range profiles {
+		f.urls[profile] = prefs.StringList(favoritesKey(profile))
+	}
+	return f
+}
+
+func (f *favorites) Has(jobURL string) bool {
+	for _, urls := range f.urls {
+		if slices.Contains(urls, jobURL) {
+			return true
+		}
+	}
+	return false
+}
+
+// Toggle stars or unstars the job of the profile and returns whether it is
+// now a favorite.
+func (f *favorites) Toggle(profile, jobURL string) bool {
+	urls := f.urls[profile]
+	starred := !slices.Contains(urls, jobURL)
+	if starred {
+		urls = append(urls, jobURL)
+	} else {
+		urls = slices.DeleteFunc(urls, func(url string) bool { return url == jobURL })
+	}
+	f.urls[profile] = urls
+	f.prefs.SetStringList(favoritesKey(profile), urls)
+	return starred
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"webservices/jenkins"
+)
+
+const (
+	filterAll      = "All jobs"
+	filterFailing  = "Failing"
+	filterRunning  = "Running"
+	filterDisabled = "Disabled"
+
+	sortName        = "Name"
+	sortLastBuild   = "Last build"
+	sortLastFailure = "Last failure"
+	sortServer      = "Server order"
+)
+
+var (
+	statusFilters = []string{filterAll, filterFailing, filterRunning, filterDisabled}
+	sortOrders    = []string{sortServer, sortName, sortLastBuild, sortLastFailure}
+)
+
+// jobFilter selects and orders the jobs shown in the list, it works on the
+// already fetched jobs only.
+type jobFilter struct {
+	Query         string
+	Status        string
+	View          string
+	Sort          string
+	FavoritesOnly bool
+}
+
+// fuzzyScore matches query as a subsequence of text ignoring case. Lower
+// scores are better matches, consecutive and word start matches score best.
+func fuzzyScore(query, text string) (int, bool) {
+	query = strings.ToLower(query)
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+
+	score, last := 0, -1
+	for _, q := range query {
+		if unicode.IsSpace(q) {
+			continue
+		}
+
+		found := -1
+		for i := last + 1; i < len(lower); i++ {
+			if lower[i] == q {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return 0, false
+		}
+
+		wordStart := found == 0 || !unicode.IsLetter(runes[found-1]) || unicode.IsUpper(runes[found])
+		switch {
+		case found == last+1:
+		case wordStart:
+			score += 1
+		default:
+			score += found - last
+		}
+		last = found
+	}
+	return score, true
+}
+
+func (f jobFilter) matchesStatus(job jenkins.Job) bool {
+	status, running := job.Status()
+	switch f.Status {
+	case filterFailing:
+		return status == jenkins.StatusFailed || status == jenkins.StatusUnstable
+	case filterRunning:
+		return running
+	case filterDisabled:
+		return status == jenkins.StatusDisabled
+	}
+	return true
+}
+
+func buildTime(build *jenkins.Build) int64 {
+	if build == nil {
+		return 0
+	}
+	return build.Timestamp
+}
+
+// apply returns the jobs to show, favorites pinned at the top.
+func (f jobFilter) apply(jobs []jenkins.Job, views []jenkins.View, favs *favorites) []jenkins.Job {
+	// The servers combined in the All servers view may each have a view of
+	// that name, a job is shown when it is in any of them.
+	var selected []jenkins.View
+	for _, view := range views {
+		if view.Name == f.View {
+			selected = append(selected, view)
+		}
+	}
+	inView := func(job jenkins.Job) bool {
+		return len(selected) == 0 || slices.ContainsFunc(selected, func(view jenkins.View) bool {
+			return view.Contains(job)
+		})
+	}
+
+	scores := map[string]int{}
+	var visible []jenkins.Job
+	for _, job := range jobs {
+		if !f.matchesStatus(job) || !inView(job) {
+			continue
+		}
+		if f.FavoritesOnly && !favs.Has(job.URL) {
+			continue
+		}
+		if f.Query != "" {
+			score, ok := fuzzyScore(f.Query, job.Title())
+			if !ok {
+				continue
+			}
+			scores[job.URL] = score
+		}
+		visible = append(visible, job)
+	}
+
+	slices.SortStableFunc(visible, func(a, b jenkins.Job) int {
+		switch f.Sort {
+		case sortName:
+			return cmp.Compare(strings.ToLower(a.Title()), strings.ToLower(b.Title()))
+		case sortLastBuild:
+			return cmp.Compare(buildTime(b.LastBuild), buildTime(a.LastBuild))
+		case sortLastFailure:
+			return cmp.Compare(buildTime(b.LastFailedBuild), buildTime(a.LastFailedBuild))
+		}
+		return 0
+	})
+	if f.Query != "" {
+		slices.SortStableFunc(visible, func(a, b jenkins.Job) int {
+			return cmp.Compare(scores[a.URL], scores[b.URL])
+		})
+	}
+	slices.SortStableFunc(visible, func(a, b jenkins.Job) int {
+		switch {
+		case favs.Has(a.URL) == favs.Has(b.URL):
+			return 0
+		case favs.Has(a.URL):
+			return -1
+		}
+		return 1
+	})
+	return visible
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"webservices/jenkins"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query string
+		text  string
+		score int
+		ok    bool
+	}{
+		{"", "deploy", 0, true},
+		{"deploy", "deploy", 0, true},
+		{"DEPLOY", "deploy", 0, true},
+		{"dep", "deploy-backend", 0, true},
+		{"db", "deploy-backend", 1, true},
+		{"db", "deployBackend", 1, true},
+		{"de ba", "deploy-backend", 1, true},
+		{"ac", "abc", 2, true},
+		{"dk", "deploy-backend", 10, true},
+		{"xyz", "deploy", 0, false},
+		{"yd", "deploy", 0, false},
+		{"deployx", "deploy", 0, false},
+	}
+	for _, test := range tests {
+		score, ok := fuzzyScore(test.query, test.text)
+		if ok != test.ok || score != test.score {
+			t.Errorf("fuzzyScore(%q, %q) = %d, %v, want %d, %v", test.query, test.text, score, ok, test.score, test.ok)
+		}
+	}
+}
+
+func TestFuzzyScoreRanking(t *testing.T) {
+	// Each text should rank better than the next one for the query.
+	tests := []struct {
+		query string
+		texts []string
+	}{
+		{"build", []string{"build", "nightly-build", "b-u-i-l-d", "big-run-in-london-daily"}},
+		{"api", []string{"api-tests", "my-api", "rapid"}},
+	}
+	for _, test := range tests {
+		previous := -1
+		for _, text := range test.texts {
+			score, ok := fuzzyScore(test.query, text)
+			if !ok {
+				t.Errorf("fuzzyScore(%q, %q) did not match", test.query, text)
+				continue
+			}
+			if score <= previous {
+				t.Errorf("fuzzyScore(%q, %q) = %d, want more than %d", test.query, text, score, previous)
+			}
+			previous = score
+		}
+	}
+}
+
+func TestApplyView(t *testing.T) {
+	job := func(url string) jenkins.Job { return jenkins.Job{Name: url, URL: url} }
+	jobs := []jenkins.Job{job("dev/a"), job("dev/b"), job("prod/c"), job("prod/d")}
+	// The views of two servers combined in the All servers view.
+	views := []jenkins.View{
+		{Name: "All", Jobs: []jenkins.Job{job("dev/a"), job("dev/b")}},
+		{Name: "Nightly", Jobs: []jenkins.Job{job("dev/b")}},
+		{Name: "All", Jobs: []jenkins.Job{job("prod/c"), job("prod/d")}},
+		{Name: "Nightly", Jobs: []jenkins.Job{job("prod/d")}},
+	}
+	tests := []struct {
+		view string
+		want string
+	}{
+		{"", "[dev/a dev/b prod/c prod/d]"},
+		{"All", "[dev/a dev/b prod/c prod/d]"},
+		{"Nightly", "[dev/b prod/d]"},
+		{"Gone", "[dev/a dev/b prod/c prod/d]"},
+	}
+	for _, test := range tests {
+		f := jobFilter{Status: filterAll, Sort: sortServer, View: test.view}
+		var names []string
+		for _, job := range f.apply(jobs, views, &favorites{urls: map[string][]string{}}) {
+			names = append(names, job.Name)
+		}
+		if got := fmt.Sprint(names); got != test.want {
+			t.Errorf("view %q shows %s, want %s", test.view, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"webservices/jenkins"
+)
+
+// historyPageSize is how many builds the history fetches at once.
+const historyPageSize = 25
+
+// buildHistory lists the past builds of a job in its own window.
+type buildHistory struct {
+	ctx    context.Context
+	app    fyne.App
+	window fyne.Window
+	client *jenkins.Client
+	job    jenkins.Job
+
+	builds []jenkins.Build
+	list   *widget.List
+	more   *widget.Button
+	status *widget.Label
+}
+
+// showBuildHistory opens the history of a job, fetching its builds page by
+// page. Fetching stops when the window is closed.
+func showBuildHistory(ctx context.Context, a fyne.App, client *jenkins.Client, job jenkins.Job) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	w := a.NewWindow("History: " + job.Title())
+	w.SetOnClosed(cancel)
+
+	h := &buildHistory{
+		ctx:    ctx,
+		app:    a,
+		window: w,
+		client: client,
+		job:    job,
+		status: widget.NewLabel("Loading builds..."),
+	}
+	h.more = widget.NewButtonWithIcon("Load more", theme.MoreVerticalIcon(), h.loadMore)
+	h.more.Hide()
+
+	h.list = widget.NewList(
+		func() int {
+			return len(h.builds)
+		},
+		func() fyne.CanvasObject {
+			title := widget.NewLabel("")
+			title.TextStyle.Bold = true
+			lines := container.NewVBox(title)
+			for range 3 {
+				line := widget.NewLabel("")
+				line.SizeName = theme.SizeNameCaptionText
+				line.Truncation = fyne.TextTruncateEllipsis
+				lines.Add(line)
+			}
+			return container.NewBorder(nil, nil, widget.NewIcon(nil), nil, lines)
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			build := h.builds[id]
+			row := o.(*fyne.Container)
+			row.Objects[1].(*widget.Icon).SetResource(buildIcon(build))
+
+			lines := row.Objects[0].(*fyne.Container).Objects
+			lines[0].(*widget.Label).SetText(buildTitle(build))
+			lines[1].(*widget.Label).SetText(buildTiming(build))
+			lines[2].(*widget.Label).SetText(buildCause(build))
+			lines[3].(*widget.Label).SetText(buildChanges(build))
+		},
+	)
+	h.list.OnSelected = func(id widget.ListItemID) {
+		h.list.Unselect(id)
+		h.showBuild(h.builds[id])
+	}
+
+	// The trends are only fetched once their tab is opened.
+	trends := newBuildTrends(ctx, client, job.URL)
+	trendsTab := container.NewTabItemWithIcon("Trends", theme.GridIcon(), trends.content)
+	tabs := container.NewAppTabs(
+		container.NewTabItemWithIcon("Builds", theme.HistoryIcon(),
+			container.NewBorder(nil, container.NewVBox(h.more, h.status), nil, nil, h.list),
+		),
+		trendsTab,
+	)
+	loaded := false
+	tabs.OnSelected = func(tab *container.TabItem) {
+		if tab == trendsTab && !loaded {
+			loaded = true
+			trends.load()
+		}
+	}
+	w.SetContent(tabs)
+	w.Resize(fyne.NewSize(600, 700))
+	w.Show()
+
+	h.loadMore()
+}
+
+// loadMore fetches the next page of older builds.
+func (h *buildHistory) loadMore() {
+	from := len(h.builds)
+	h.more.Disable()
+	h.status.SetText("Loading builds...")
+
+	go func() {
+		builds, err := h.client.BuildHistory(h.ctx, h.job.URL, from, from+historyPageSize)
+		fyne.Do(func() {
+			h.more.Enable()
+			if err != nil {
+				h.status.SetText("Error fetching builds: " + err.Error())
+				return
+			}
+
+			h.builds = append(h.builds, builds...)
+			h.list.Refresh()
+			if len(builds) < historyPageSize {
+				h.more.Hide()
+			} else {
+				h.more.Show()
+			}
+			h.status.SetText(fmt.Sprintf("%d builds", len(h.builds)))
+		})
+	}()
+}
+
+// showBuild shows everything known about a build and what can be opened
+// from it.
+func (h *buildHistory) showBuild(build jenkins.Build) {
+	details := widget.NewLabel(strings.Join([]string{buildTiming(build), buildCause(build)}, "\n"))
+	details.Wrapping = fyne.TextWrapWord
+
+	changes := container.NewVBox()
+	for _, change := range build.Changes() {
+		label := widget.NewLabel(describeChange(change))
+		label.Wrapping = fyne.TextWrapWord
+		changes.Add(label)
+	}
+	if len(changes.Objects) == 0 {
+		changes.Add(widget.NewLabel("No changes"))
+	}
+
+	title := fmt.Sprintf("%s #%d", h.job.Title(), build.Number)
+	link, _ := url.Parse(build.URL)
+	actions := container.NewHBox(
+		widget.NewButtonWithIcon("Console", theme.DocumentIcon(), func() {
+			showLogViewer(h.ctx, h.app, h.client, title, build.URL)
+		}),
+		widget.NewButtonWithIcon("Tests", theme.ListIcon(), func() {
+			showTestReport(h.ctx, h.app, h.client, title, build)
+		}),
+		widget.NewButtonWithIcon("Artifacts", theme.DownloadIcon(), func() {
+			showArtifacts(h.ctx, h.app, h.client, title, build)
+		}),
+		widget.NewHyperlink("Open on Jenkins", link),
+	)
+
+	content := container.NewBorder(
+		container.NewVBox(details, widget.NewSeparator()), actions, nil, nil,
+		container.NewVScroll(changes),
+	)
+	info := dialog.NewCustom(buildTitle(build), "Close", content, h.window)
+	info.Resize(fyne.NewSize(500, 400))
+	info.Show()
+}
+
+func buildTitle(build jenkins.Build) string {
+	if build.Building {
+		return fmt.Sprintf("#%d Running", build.Number)
+	}
+	return fmt.Sprintf("#%d %s", build.Number, build.Result)
+}
+
+func buildTiming(build jenkins.Build) string {
+	started := build.Started()
+	return fmt.Sprintf("%s (%s) · %s", started.Format("Jan 2 15:04"), timeAgo(started), build.Elapsed().Round(time.Second))
+}
+
+func buildCause(build jenkins.Build) string {
+	for _, cause := range build.Causes() {
+		if cause.ShortDescription != "" {
+			return cause.ShortDescription
+		}
+	}
+	if user := build.User(); user != "" {
+		return "Started by " + user
+	}
+	return "Unknown cause"
+}
+
+func buildChanges(build jenkins.Build) string {
+	changes := build.Changes()
+	switch len(changes) {
+	case 0:
+		return "No changes"
+	case 1:
+		return describeChange(changes[0])
+	}
+	return fmt.Sprintf("%s and %d more", describeChange(changes[0]), len(changes)-1)
+}
+
+func describeChange(change jenkins.Change) string {
+	id := change.CommitID
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	message, _, _ := strings.Cut(strings.TrimSpace(change.Msg), "\n")
+	if change.Author.FullName != "" {
+		message += " (" + change.Author.FullName + ")"
+	}
+	return strings.TrimSpace(id + " " + message)
+}
@@ -0,0 +1,86 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Artifact is a file archived by a build.
+type Artifact struct {
+	DisplayPath  string `json:"displayPath"`
+	FileName     string `json:"fileName"`
+	RelativePath string `json:"relativePath"`
+}
+
+// URL returns where the artifact of the build can be downloaded from.
+func (a *Artifact) URL(buildURL string) string {
+	segments := strings.Split(a.RelativePath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.TrimRight(buildURL, "/") + "/artifact/" + strings.Join(segments, "/")
+}
+
+// Artifacts lists the files archived by a build.
+func (c *Client) Artifacts(ctx context.Context, buildURL string) ([]Artifact, error) {
+	var build struct {
+		Artifacts []Artifact `json:"artifacts"`
+	}
+	if err := c.getJSON(ctx, apiURL(buildURL, "artifacts[displayPath,fileName,relativePath]"), &build); err != nil {
+		return nil, err
+	}
+	return build.Artifacts, nil
+}
+
+// Download is the content of an artifact being downloaded.
+type Download struct {
+	Body io.ReadCloser
+	// Offset is where Body starts in the file. It is zero when the server
+	// does not support resuming, in which case the file is sent whole.
+	Offset int64
+	// Size is the size of the whole file, -1 when unknown.
+	Size int64
+}
+
+// DownloadArtifact starts downloading the artifact at artifactURL, resuming
+// at byte offset when the server supports range requests. The caller must
+// close the body. Artifacts can be large, so the download is only bound by
+// ctx.
+func (c *Client) DownloadArtifact(ctx context.Context, artifactURL string, offset int64) (*Download, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, artifactURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	res, err := c.Do(req)
+	var status *StatusError
+	if offset > 0 && errors.As(err, &status) && status.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		// The file was already downloaded whole.
+		return &Download{Body: io.NopCloser(strings.NewReader("")), Offset: offset, Size: offset}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	download := &Download{Body: res.Body, Size: res.ContentLength}
+	if res.StatusCode == http.StatusPartialContent {
+		download.Offset = offset
+		download.Size = -1
+		// Content-Range: bytes start-end/size
+		if _, size, ok := strings.Cut(res.Header.Get("Content-Range"), "/"); ok {
+			if size, err := strconv.ParseInt(size, 10, 64); err == nil {
+				download.Size = size
+			}
+		}
+	}
+	return download, nil
+}
@@ -0,0 +1,104 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestArtifactURL(t *testing.T) {
+	tests := []struct {
+		relativePath string
+		want         string
+	}{
+		{"app.apk", "https://jenkins/job/a/1/artifact/app.apk"},
+		{"build/outputs/app.apk", "https://jenkins/job/a/1/artifact/build/outputs/app.apk"},
+		{"reports/test results #1.html", "https://jenkins/job/a/1/artifact/reports/test%20results%20%231.html"},
+		{"a?b/100%.txt", "https://jenkins/job/a/1/artifact/a%3Fb/100%25.txt"},
+	}
+	for _, test := range tests {
+		artifact := Artifact{RelativePath: test.relativePath}
+		if got := artifact.URL("https://jenkins/job/a/1/"); got != test.want {
+			t.Errorf("URL of %q = %q, want %q", test.relativePath, got, test.want)
+		}
+	}
+}
+
+func TestDownloadArtifact(t *testing.T) {
+	const content = "0123456789"
+	tests := []struct {
+		name       string
+		offset     int64
+		handler    http.HandlerFunc
+		wantRange  string
+		wantBody   string
+		wantOffset int64
+		wantSize   int64
+	}{
+		{"whole", 0, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, content)
+		}, "", content, 0, 10},
+		{"resumed", 4, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Range", "bytes 4-9/10")
+			w.WriteHeader(http.StatusPartialContent)
+			fmt.Fprint(w, content[4:])
+		}, "bytes=4-", content[4:], 4, 10},
+		{"resumed without size", 4, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Range", "bytes 4-9/*")
+			w.WriteHeader(http.StatusPartialContent)
+			fmt.Fprint(w, content[4:])
+		}, "bytes=4-", content[4:], 4, -1},
+		{"complete", 10, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Range", "bytes */10")
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+		}, "bytes=10-", "", 10, 10},
+		{"no range support", 4, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, content)
+		}, "bytes=4-", content, 0, 10},
+	}
+	for _, test := range tests {
+		var gotRange string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotRange = r.Header.Get("Range")
+			test.handler(w, r)
+		}))
+
+		c := NewClient(server.URL, "", "")
+		download, err := c.DownloadArtifact(context.Background(), server.URL+"/job/a/1/artifact/app.apk", test.offset)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			server.Close()
+			continue
+		}
+		body, err := io.ReadAll(download.Body)
+		download.Body.Close()
+		if err != nil {
+			t.Errorf("%s: reading: %v", test.name, err)
+		}
+
+		if gotRange != test.wantRange {
+			t.Errorf("%s: sent Range %q, want %q", test.name, gotRange, test.wantRange)
+		}
+		if string(body) != test.wantBody || download.Offset != test.wantOffset || download.Size != test.wantSize {
+			t.Errorf("%s: got %q at %d of %d, want %q at %d of %d", test.name,
+				body, download.Offset, download.Size, test.wantBody, test.wantOffset, test.wantSize)
+		}
+		server.Close()
+	}
+}
+
+func TestDownloadArtifactNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	c := NewClient(server.URL, "", "")
+	c.Retries = 0
+	_, err := c.DownloadArtifact(context.Background(), server.URL+"/job/a/1/artifact/missing", 0)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("DownloadArtifact returned %v, want ErrNotFound", err)
+	}
+}
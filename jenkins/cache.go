@@ -0,0 +1,69 @@
+package jenkins
+
+import (
+	"net/http"
+	"sync"
+)
+
+// maxCacheEntries bounds the memory used by the response cache.
+const maxCacheEntries = 256
+
+// responseCache keeps the responses which came with an ETag or a
+// Last-Modified date, so that they can be revalidated with a conditional
+// request instead of being downloaded again.
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+func (c *responseCache) get(url string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[url]
+	return entry, ok
+}
+
+// put stores the body of res when it can be revalidated later.
+func (c *responseCache) put(url string, res *http.Response, body []byte) {
+	entry := cacheEntry{
+		etag:         res.Header.Get("ETag"),
+		lastModified: res.Header.Get("Last-Modified"),
+		body:         body,
+	}
+	if entry.etag == "" && entry.lastModified == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = map[string]cacheEntry{}
+	}
+	if _, ok := c.entries[url]; !ok && len(c.entries) >= maxCacheEntries {
+		for evicted := range c.entries {
+			delete(c.entries, evicted)
+			break
+		}
+	}
+	c.entries[url] = entry
+}
+
+// conditional returns the headers revalidating the entry.
+func (e cacheEntry) conditional() http.Header {
+	header := http.Header{}
+	if e.etag != "" {
+		header.Set("If-None-Match", e.etag)
+	}
+	if e.lastModified != "" {
+		header.Set("If-Modified-Since", e.lastModified)
+	}
+	return header
+}
@@ -0,0 +1,119 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRevalidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator string
+		condition string
+		value     string
+	}{
+		{"etag", "ETag", "If-None-Match", `"v1"`},
+		{"last modified", "Last-Modified", "If-Modified-Since", "Wed, 14 Oct 2026 10:00:00 GMT"},
+	}
+	for _, test := range tests {
+		var requests, revalidated int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.Header.Get(test.condition) == test.value {
+				revalidated++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set(test.validator, test.value)
+			fmt.Fprint(w, `{"name":"a","url":"/job/a/"}`)
+		}))
+
+		c := NewClient(server.URL, "", "")
+		for range 3 {
+			job, err := c.GetJob(context.Background(), "/job/a/")
+			if err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+			if job.Name != "a" {
+				t.Errorf("%s: GetJob = %+v, want the cached job", test.name, job)
+			}
+		}
+		if requests != 3 || revalidated != 2 {
+			t.Errorf("%s: %d requests, %d revalidated, want 3 and 2", test.name, requests, revalidated)
+		}
+		server.Close()
+	}
+}
+
+func TestRevalidationChanged(t *testing.T) {
+	version := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := fmt.Sprintf(`"v%d"`, version)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprintf(w, `{"name":"a","url":"/job/a/","displayName":"version %d"}`, version)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "", "")
+	for _, want := range []struct {
+		version     int
+		displayName string
+	}{{1, "version 1"}, {1, "version 1"}, {2, "version 2"}, {2, "version 2"}} {
+		version = want.version
+		job, err := c.GetJob(context.Background(), "/job/a/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.DisplayName != want.displayName {
+			t.Errorf("GetJob at version %d = %q, want %q", want.version, job.DisplayName, want.displayName)
+		}
+	}
+}
+
+func TestNoValidator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+			t.Error("a response without validators was revalidated")
+		}
+		fmt.Fprint(w, `{"name":"a","url":"/job/a/"}`)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "", "")
+	for range 2 {
+		if _, err := c.GetJob(context.Background(), "/job/a/"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := c.cache.get(c.URL("/job/a/")); ok {
+		t.Error("a response without validators was cached")
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	var cache responseCache
+	res := &http.Response{Header: http.Header{"Etag": {`"v1"`}}}
+	for i := range maxCacheEntries + 10 {
+		cache.put(fmt.Sprintf("https://jenkins/job/%d/", i), res, []byte("{}"))
+	}
+	if len(cache.entries) != maxCacheEntries {
+		t.Errorf("the cache has %d entries, want %d", len(cache.entries), maxCacheEntries)
+	}
+
+	// Replacing an entry evicts nothing.
+	var url string
+	for url = range cache.entries {
+		break
+	}
+	cache.put(url, res, []byte("[]"))
+	if entry, _ := cache.get(url); string(entry.body) != "[]" || len(cache.entries) != maxCacheEntries {
+		t.Errorf("replacing %s gave %q with %d entries", url, entry.body, len(cache.entries))
+	}
+}
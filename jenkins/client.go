@@ -0,0 +1,396 @@
+// Package jenkins is a small client for the Jenkins remote access API.
+package jenkins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrUnauthorized = errors.New("jenkins: unauthorized")
+	ErrForbidden    = errors.New("jenkins: forbidden")
+	ErrNotFound     = errors.New("jenkins: not found")
+	ErrNoLocation   = errors.New("jenkins: response has no queue location")
+)
+
+// StatusError is returned when Jenkins answers with a non 2xx status code.
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("jenkins: %s %s: %s", e.Method, e.URL, http.StatusText(e.StatusCode))
+}
+
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+type Client struct {
+	BaseURL  string
+	Username string
+	Token    string
+	HTTP     *http.Client
+
+	// Timeout bounds every API call, including reading the response. Only
+	// artifact downloads, which can be large, are not bound by it.
+	Timeout time.Duration
+	// Retries is how many times failed GET requests are tried again, waiting
+	// RetryDelay at first and twice as long on every retry.
+	Retries    int
+	RetryDelay time.Duration
+	// OnlineChanged is called when the server stops or starts answering,
+	// from the goroutine of the request.
+	OnlineChanged func(online bool)
+
+	mu           sync.Mutex
+	cachedCrumb  *Crumb
+	crumbFetched bool
+	offline      atomic.Bool
+	cache        responseCache
+}
+
+func NewClient(baseURL, username, token string) *Client {
+	jar, _ := cookiejar.New(nil)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = DefaultTimeout
+
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Username:   username,
+		Token:      token,
+		HTTP:       &http.Client{Jar: jar, Transport: transport},
+		Timeout:    DefaultTimeout,
+		Retries:    DefaultRetries,
+		RetryDelay: DefaultRetryDelay,
+	}
+}
+
+// URL resolves path against the base URL. Absolute URLs, such as the ones
+// returned by Jenkins in its responses, are used as is.
+func (c *Client) URL(path string) string {
+	if c.BaseURL != "" && strings.HasPrefix(path, c.BaseURL) {
+		return path
+	}
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return c.BaseURL + path
+}
+
+func (c *Client) NewRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.URL(path), body)
+	if err != nil {
+		return nil, err
+	}
+	if c.Username != "" || c.Token != "" {
+		req.SetBasicAuth(c.Username, c.Token)
+	}
+	return req, nil
+}
+
+// Do sends the request and returns a *StatusError for non 2xx responses.
+// On success the caller must close the response body.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	res, err := c.do(req)
+	c.setOnline(err)
+	return res, err
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	res, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		defer res.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+		return nil, &StatusError{
+			Method:     req.Method,
+			URL:        req.URL.String(),
+			StatusCode: res.StatusCode,
+			Body:       string(body),
+		}
+	}
+	return res, nil
+}
+
+// get sends a GET request, retrying with exponential backoff while it fails
+// with temporary errors.
+func (c *Client) get(ctx context.Context, path string) (*http.Response, error) {
+	return c.getWithHeader(ctx, path, nil)
+}
+
+func (c *Client) getWithHeader(ctx context.Context, path string, header http.Header) (*http.Response, error) {
+	for retry := 0; ; retry++ {
+		req, err := c.NewRequest(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		res, err := c.Do(req)
+		if err == nil || retry >= c.Retries || !IsTemporary(err) {
+			return res, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(c.backoff(retry)):
+		}
+	}
+}
+
+func (c *Client) post(ctx context.Context, path string, data url.Values) (*http.Response, error) {
+	var body []byte
+	if data != nil {
+		body = []byte(data.Encode())
+	}
+	return c.postBody(ctx, path, "application/x-www-form-urlencoded", body)
+}
+
+// postBody sends a POST with a CSRF crumb attached. When Jenkins rejects the
+// crumb, e.g. because the session expired, a fresh one is fetched and the
+// request is retried once.
+func (c *Client) postBody(ctx context.Context, path string, contentType string, body []byte) (*http.Response, error) {
+	res, err := c.postOnce(ctx, path, contentType, body)
+	if isCrumbError(err) {
+		c.resetCrumb()
+		res, err = c.postOnce(ctx, path, contentType, body)
+	}
+	return res, err
+}
+
+func (c *Client) postOnce(ctx context.Context, path string, contentType string, body []byte) (*http.Response, error) {
+	crumb, err := c.crumb(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.NewRequest(ctx, http.MethodPost, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if crumb != nil {
+		req.Header.Set(crumb.Field, crumb.Value)
+	}
+	return c.Do(req)
+}
+
+// getJSON decodes the response to a GET request into v. Responses which can
+// be revalidated are cached, and reused when Jenkins answers that they did
+// not change.
+func (c *Client) getJSON(ctx context.Context, path string, v any) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	fullURL := c.URL(path)
+	entry, cached := c.cache.get(fullURL)
+	var header http.Header
+	if cached {
+		header = entry.conditional()
+	}
+
+	res, err := c.getWithHeader(ctx, fullURL, header)
+	var status *StatusError
+	if cached && errors.As(err, &status) && status.StatusCode == http.StatusNotModified {
+		return json.Unmarshal(entry.body, v)
+	}
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	c.cache.put(fullURL, res, body)
+	return json.Unmarshal(body, v)
+}
+
+// withTimeout bounds a single API call with the timeout of the client.
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.Timeout)
+}
+
+// apiURL appends the json api suffix to a Jenkins object URL, along with the
+// tree of fields to return when not empty.
+func apiURL(objectURL, tree string) string {
+	api := strings.TrimRight(objectURL, "/") + "/api/json"
+	if tree != "" {
+		api += "?tree=" + url.QueryEscape(tree)
+	}
+	return api
+}
+
+// The tree query parameter limits the responses to the fields the types of
+// this package decode, which keeps them small on large instances. Lists of
+// builds are capped with the {from,to} range syntax.
+const (
+	buildTree      = "number,url,result,building,timestamp,duration"
+	buildFullTree  = buildTree + ",fullDisplayName,estimatedDuration"
+	parameterTree  = "_class,type,name,description,defaultParameterValue[_class,value],choices,projectName"
+	jobSummaryTree = "_class,name,displayName,url,color,healthReport[score,description]," +
+		"lastBuild[" + buildTree + "],lastFailedBuild[" + buildTree + "]"
+	jobsTree  = "jobs[" + jobSummaryTree + "]"
+	stateTree = jobsTree + ",views[name,url,jobs[url]]"
+	jobTree   = jobSummaryTree + ",fullName,builds[" + buildTree + "]{0,50}," +
+		"property[_class,parameterDefinitions[" + parameterTree + "]]"
+	queueItemTree = "id,task[name,url],why,blocked,buildable,stuck,cancelled,inQueueSince,executable[number,url]"
+)
+
+// ListJobs lists the jobs at the top level of the server.
+func (c *Client) ListJobs(ctx context.Context) ([]Job, error) {
+	return c.ListJobsIn(ctx, "")
+}
+
+// ListJobsIn lists the children of a folder, organization folder or
+// multibranch project. An empty folderURL lists the top level jobs.
+func (c *Client) ListJobsIn(ctx context.Context, folderURL string) ([]Job, error) {
+	state := State{}
+	if err := c.getJSON(ctx, apiURL(folderURL, jobsTree), &state); err != nil {
+		return nil, err
+	}
+	return state.Jobs, nil
+}
+
+// GetState returns the jobs of a folder, or of the top level when folderURL
+// is empty, along with its views.
+func (c *Client) GetState(ctx context.Context, folderURL string) (*State, error) {
+	state := &State{}
+	if err := c.getJSON(ctx, apiURL(folderURL, stateTree), state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// GetJob fetches a job, jobURL being either the url reported by Jenkins or a
+// path like /job/name/.
+func (c *Client) GetJob(ctx context.Context, jobURL string) (*Job, error) {
+	job := &Job{}
+	if err := c.getJSON(ctx, apiURL(jobURL, jobTree), job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Build triggers a job without parameters and returns the queue item URL.
+func (c *Client) Build(ctx context.Context, jobURL string) (string, error) {
+	return c.trigger(ctx, strings.TrimRight(jobURL, "/")+"/build", nil)
+}
+
+// BuildWithParameters triggers a parameterised job and returns the queue item URL.
+func (c *Client) BuildWithParameters(ctx context.Context, jobURL string, params url.Values) (string, error) {
+	return c.trigger(ctx, strings.TrimRight(jobURL, "/")+"/buildWithParameters", params)
+}
+
+// File is uploaded along the parameters of a job with file parameters.
+type File struct {
+	Name    string
+	Content io.Reader
+}
+
+// BuildWithFiles triggers a parameterised job uploading files for its file
+// parameters, keyed by parameter name, and returns the queue item URL.
+func (c *Client) BuildWithFiles(ctx context.Context, jobURL string, params url.Values, files map[string]File) (string, error) {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	for name, values := range params {
+		for _, value := range values {
+			if err := form.WriteField(name, value); err != nil {
+				return "", err
+			}
+		}
+	}
+	for name, file := range files {
+		part, err := form.CreateFormFile(name, file.Name)
+		if err != nil {
+			return "", err
+		}
+		if _, err := io.Copy(part, file.Content); err != nil {
+			return "", err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	res, err := c.postBody(ctx, strings.TrimRight(jobURL, "/")+"/buildWithParameters", form.FormDataContentType(), body.Bytes())
+	if err != nil {
+		return "", err
+	}
+	return queueLocation(res)
+}
+
+func (c *Client) trigger(ctx context.Context, path string, params url.Values) (string, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	res, err := c.post(ctx, path, params)
+	if err != nil {
+		return "", err
+	}
+	return queueLocation(res)
+}
+
+func queueLocation(res *http.Response) (string, error) {
+	defer res.Body.Close()
+
+	location := res.Header.Get("Location")
+	if location == "" {
+		return "", ErrNoLocation
+	}
+	return location, nil
+}
+
+func (c *Client) GetQueueItem(ctx context.Context, queueURL string) (*QueueItem, error) {
+	item := &QueueItem{}
+	if err := c.getJSON(ctx, apiURL(queueURL, queueItemTree), item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (c *Client) GetBuild(ctx context.Context, buildURL string) (*Build, error) {
+	build := &Build{}
+	if err := c.getJSON(ctx, apiURL(buildURL, buildFullTree), build); err != nil {
+		return nil, err
+	}
+	return build, nil
+}
@@ -0,0 +1,168 @@
+package jenkins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestURL(t *testing.T) {
+	c := NewClient("https://ci.example.com/jenkins/", "", "")
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/job/a/", "https://ci.example.com/jenkins/job/a/"},
+		{"job/a/", "https://ci.example.com/jenkins/job/a/"},
+		{"https://ci.example.com/jenkins/job/a/", "https://ci.example.com/jenkins/job/a/"},
+		{"http://other.example.com/job/a/", "http://other.example.com/job/a/"},
+	}
+	for _, test := range tests {
+		if got := c.URL(test.path); got != test.want {
+			t.Errorf("URL(%q) = %q, want %q", test.path, got, test.want)
+		}
+	}
+}
+
+func TestJobPath(t *testing.T) {
+	tests := []struct {
+		fullName string
+		want     string
+	}{
+		{"job", "/job/job/"},
+		{"folder/job", "/job/folder/job/job/"},
+		{"/folder/feature%2Fx/", "/job/folder/job/feature%252Fx/"},
+		{"with space", "/job/with%20space/"},
+	}
+	for _, test := range tests {
+		if got := JobPath(test.fullName); got != test.want {
+			t.Errorf("JobPath(%q) = %q, want %q", test.fullName, got, test.want)
+		}
+	}
+}
+
+func TestValueUnmarshal(t *testing.T) {
+	tests := []struct {
+		json string
+		want Value
+	}{
+		{`"text"`, "text"},
+		{`true`, "true"},
+		{`42`, "42"},
+		{`null`, ""},
+	}
+	for _, test := range tests {
+		var v Value
+		if err := json.Unmarshal([]byte(test.json), &v); err != nil {
+			t.Errorf("Unmarshal(%s): %v", test.json, err)
+		} else if v != test.want {
+			t.Errorf("Unmarshal(%s) = %q, want %q", test.json, v, test.want)
+		}
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		status int
+		target error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+	}
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "nope", test.status)
+		}))
+		c := NewClient(server.URL, "user", "token")
+		c.Retries = 0
+
+		_, err := c.GetJob(context.Background(), "/job/a/")
+		if !errors.Is(err, test.target) {
+			t.Errorf("status %d: got %v, want %v", test.status, err, test.target)
+		}
+		var status *StatusError
+		if !errors.As(err, &status) || status.StatusCode != test.status {
+			t.Errorf("status %d: got %v, want a *StatusError", test.status, err)
+		}
+		server.Close()
+	}
+}
+
+func TestBuild(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, token, _ := r.BasicAuth()
+		switch {
+		case user != "user" || token != "token":
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case r.URL.Path == "/job/a/build" && r.Method == http.MethodPost:
+			w.Header().Set("Location", "http://"+r.Host+"/queue/item/7/")
+			w.WriteHeader(http.StatusCreated)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "user", "token")
+	location, err := c.Build(context.Background(), "/job/a/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := server.URL + "/queue/item/7/"; location != want {
+		t.Errorf("Build returned %q, want %q", location, want)
+	}
+}
+
+func TestBuildWithFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/a/buildWithParameters" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if got := r.MultipartForm.Value["BRANCH"]; len(got) != 1 || got[0] != "main" {
+			http.Error(w, fmt.Sprintf("BRANCH = %q", got), http.StatusBadRequest)
+			return
+		}
+		if got := r.MultipartForm.Value["TAGS"]; len(got) != 2 || got[0] != "a" || got[1] != "b" {
+			http.Error(w, fmt.Sprintf("TAGS = %q", got), http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("config.yml")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+		if header.Filename != "local.yml" || string(content) != "debug: true\n" {
+			http.Error(w, fmt.Sprintf("file %s = %q", header.Filename, content), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Location", "http://"+r.Host+"/queue/item/8/")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "", "")
+	params := url.Values{"BRANCH": {"main"}, "TAGS": {"a", "b"}}
+	files := map[string]File{"config.yml": {Name: "local.yml", Content: strings.NewReader("debug: true\n")}}
+	location, err := c.BuildWithFiles(context.Background(), server.URL+"/job/a/", params, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := server.URL + "/queue/item/8/"; location != want {
+		t.Errorf("BuildWithFiles returned %q, want %q", location, want)
+	}
+}
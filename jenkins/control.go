@@ -0,0 +1,38 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Stop aborts a running build, like the stop button of the Jenkins UI.
+func (c *Client) Stop(ctx context.Context, buildURL string) error {
+	return c.postAction(ctx, strings.TrimRight(buildURL, "/")+"/stop")
+}
+
+// Term forcibly terminates a pipeline build which did not react to Stop.
+func (c *Client) Term(ctx context.Context, buildURL string) error {
+	return c.postAction(ctx, strings.TrimRight(buildURL, "/")+"/term")
+}
+
+// Kill hard kills a pipeline build which did not react to Term.
+func (c *Client) Kill(ctx context.Context, buildURL string) error {
+	return c.postAction(ctx, strings.TrimRight(buildURL, "/")+"/kill")
+}
+
+// CancelQueueItem removes an item that is still waiting in the queue.
+func (c *Client) CancelQueueItem(ctx context.Context, id int) error {
+	return c.postAction(ctx, fmt.Sprintf("/queue/cancelItem?id=%d", id))
+}
+
+func (c *Client) postAction(ctx context.Context, path string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	res, err := c.post(ctx, path, nil)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
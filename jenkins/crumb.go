@@ -0,0 +1,56 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Crumb is the CSRF token Jenkins requires on POST requests when CSRF
+// protection is enabled. The crumb is bound to the session cookie it was
+// issued with, which the client keeps in its cookie jar.
+type Crumb struct {
+	Field string `json:"crumbRequestField"`
+	Value string `json:"crumb"`
+}
+
+// crumb returns the cached crumb, fetching it when needed. A nil crumb with
+// no error means that the server does not use CSRF protection.
+func (c *Client) crumb(ctx context.Context) (*Crumb, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.crumbFetched {
+		return c.cachedCrumb, nil
+	}
+
+	crumb := &Crumb{}
+	err := c.getJSON(ctx, "/crumbIssuer/api/json", crumb)
+	if errors.Is(err, ErrNotFound) {
+		crumb, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.cachedCrumb = crumb
+	c.crumbFetched = true
+	return crumb, nil
+}
+
+func (c *Client) resetCrumb() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cachedCrumb = nil
+	c.crumbFetched = false
+}
+
+func isCrumbError(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusForbidden {
+		return false
+	}
+	return strings.Contains(strings.ToLower(statusErr.Body), "crumb")
+}
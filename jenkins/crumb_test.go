@@ -0,0 +1,116 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// crumbServer issues a new crumb on every request to the crumb issuer, and
+// only accepts POST requests carrying the latest one.
+type crumbServer struct {
+	issued  atomic.Int32
+	posts   atomic.Int32
+	current atomic.Value
+}
+
+func (s *crumbServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/crumbIssuer/api/json":
+		crumb := fmt.Sprintf("crumb-%d", s.issued.Add(1))
+		s.current.Store(crumb)
+		fmt.Fprintf(w, `{"crumbRequestField":"Jenkins-Crumb","crumb":%q}`, crumb)
+	case "/job/a/build":
+		s.posts.Add(1)
+		if r.Header.Get("Jenkins-Crumb") != s.current.Load() {
+			http.Error(w, "No valid crumb was included in the request", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Location", "/queue/item/1/")
+		w.WriteHeader(http.StatusCreated)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestCrumbIsCached(t *testing.T) {
+	crumbs := &crumbServer{}
+	server := httptest.NewServer(crumbs)
+	defer server.Close()
+
+	c := NewClient(server.URL, "", "")
+	for range 3 {
+		if _, err := c.Build(context.Background(), "/job/a/"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if issued := crumbs.issued.Load(); issued != 1 {
+		t.Errorf("fetched %d crumbs, want 1", issued)
+	}
+}
+
+func TestCrumbRetryOn403(t *testing.T) {
+	crumbs := &crumbServer{}
+	server := httptest.NewServer(crumbs)
+	defer server.Close()
+
+	c := NewClient(server.URL, "", "")
+	if _, err := c.Build(context.Background(), "/job/a/"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The session expired, so Jenkins no longer accepts the cached crumb.
+	c.cachedCrumb.Value = "expired"
+	if _, err := c.Build(context.Background(), "/job/a/"); err != nil {
+		t.Fatalf("Build did not retry with a fresh crumb: %v", err)
+	}
+	if issued := crumbs.issued.Load(); issued != 2 {
+		t.Errorf("fetched %d crumbs, want 2", issued)
+	}
+	if posts := crumbs.posts.Load(); posts != 3 {
+		t.Errorf("sent %d posts, want 3", posts)
+	}
+}
+
+func TestNoCrumbIssuer(t *testing.T) {
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/a/build" {
+			http.NotFound(w, r)
+			return
+		}
+		posts++
+		w.Header().Set("Location", "/queue/item/1/")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "", "")
+	if _, err := c.Build(context.Background(), "/job/a/"); err != nil {
+		t.Fatal(err)
+	}
+	if posts != 1 {
+		t.Errorf("sent %d posts, want 1", posts)
+	}
+}
+
+func TestIsCrumbError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&StatusError{StatusCode: http.StatusForbidden, Body: "No valid crumb was included"}, true},
+		{&StatusError{StatusCode: http.StatusForbidden, Body: "Missing the Job/Build permission"}, false},
+		{&StatusError{StatusCode: http.StatusBadRequest, Body: "crumb"}, false},
+		{fmt.Errorf("wrapped: %w", &StatusError{StatusCode: http.StatusForbidden, Body: "Crumb"}), true},
+		{nil, false},
+	}
+	for _, test := range tests {
+		if got := isCrumbError(test.err); got != test.want {
+			t.Errorf("isCrumbError(%v) = %v, want %v", test.err, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Cause tells why a build was started, e.g. by a user, a timer or an SCM
+// change.
+type Cause struct {
+	Class            string `json:"_class"`
+	ShortDescription string `json:"shortDescription"`
+	UserID           string `json:"userId"`
+	UserName         string `json:"userName"`
+}
+
+// Action is one of the actions attached to a build. Only the causes and the
+// time in queue reported by the Metrics plugin are decoded.
+type Action struct {
+	Class                 string  `json:"_class"`
+	Causes                []Cause `json:"causes,omitempty"`
+	QueuingDurationMillis int64   `json:"queuingDurationMillis,omitempty"`
+}
+
+type Author struct {
+	FullName string `json:"fullName"`
+}
+
+// Change is a commit included in a build.
+type Change struct {
+	CommitID  string `json:"commitId"`
+	Msg       string `json:"msg"`
+	Author    Author `json:"author"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type ChangeSet struct {
+	Kind  string   `json:"kind"`
+	Items []Change `json:"items"`
+}
+
+// Causes returns why the build was started.
+func (b *Build) Causes() []Cause {
+	var causes []Cause
+	for _, action := range b.Actions {
+		causes = append(causes, action.Causes...)
+	}
+	return causes
+}
+
+// User returns the name of the user who started the build, if any.
+func (b *Build) User() string {
+	for _, cause := range b.Causes() {
+		if cause.UserName != "" {
+			return cause.UserName
+		}
+		if cause.UserID != "" {
+			return cause.UserID
+		}
+	}
+	return ""
+}
+
+// QueueTime returns how long the build waited in the queue, which Jenkins
+// only reports with the Metrics plugin installed.
+func (b *Build) QueueTime() (time.Duration, bool) {
+	for _, action := range b.Actions {
+		if action.Class == "jenkins.metrics.impl.TimeInQueueAction" {
+			return time.Duration(action.QueuingDurationMillis) * time.Millisecond, true
+		}
+	}
+	return 0, false
+}
+
+// Changes returns the commits included in the build. Freestyle jobs report
+// a single change set and pipelines one per checkout.
+func (b *Build) Changes() []Change {
+	changes := b.ChangeSet.Items
+	for _, set := range b.ChangeSets {
+		changes = append(changes, set.Items...)
+	}
+	return changes
+}
+
+const (
+	changeTree  = "kind,items[commitId,msg,author[fullName],timestamp]"
+	historyTree = buildFullTree + ",actions[_class,causes[_class,shortDescription,userId,userName],queuingDurationMillis]," +
+		"changeSet[" + changeTree + "],changeSets[" + changeTree + "]"
+)
+
+// BuildHistory returns the builds of a job from the most recent one, from
+// and to being the range of builds to return like for slices.
+func (c *Client) BuildHistory(ctx context.Context, jobURL string, from, to int) ([]Build, error) {
+	var job struct {
+		Builds []Build `json:"allBuilds"`
+	}
+	tree := fmt.Sprintf("allBuilds[%s]{%d,%d}", historyTree, from, to)
+	if err := c.getJSON(ctx, apiURL(jobURL, tree), &job); err != nil {
+		return nil, err
+	}
+	return job.Builds, nil
+}
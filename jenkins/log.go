@@ -0,0 +1,68 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProgressiveText returns the console output of a build starting at byte
+// offset start, the offset to continue from and whether more output is
+// expected because the build is still running.
+func (c *Client) ProgressiveText(ctx context.Context, buildURL string, start int64) (string, int64, bool, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	path := fmt.Sprintf("%s/logText/progressiveText?start=%d", strings.TrimRight(buildURL, "/"), start)
+	res, err := c.get(ctx, path)
+	if err != nil {
+		return "", start, false, err
+	}
+	defer res.Body.Close()
+
+	text, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", start, false, err
+	}
+
+	next := start + int64(len(text))
+	if size, err := strconv.ParseInt(res.Header.Get("X-Text-Size"), 10, 64); err == nil {
+		next = size
+	}
+	more := res.Header.Get("X-More-Data") == "true"
+	return string(text), next, more, nil
+}
+
+// StreamLog calls write with every new piece of console output of the build
+// until the build finishes or ctx is cancelled, riding out temporary
+// failures for a few minutes.
+func (c *Client) StreamLog(ctx context.Context, buildURL string, interval time.Duration, write func(string)) error {
+	var start int64
+	lastSuccess := time.Now()
+	for {
+		text, next, more, err := c.ProgressiveText(ctx, buildURL, start)
+		switch {
+		case tolerate(err, lastSuccess):
+		case err != nil:
+			return err
+		default:
+			lastSuccess = time.Now()
+			if text != "" {
+				write(text)
+			}
+			if !more {
+				return nil
+			}
+			start = next
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrQueueItemCancelled = errors.New("jenkins: queue item cancelled")
+
+// Progress is reported while following a triggered build. Queue is set while
+// the item waits in the queue and Build once the executable has started.
+type Progress struct {
+	Queue *QueueItem
+	Build *Build
+}
+
+// Follow polls the queue item at queueURL until it starts an executable and
+// then polls that exact build until it finishes, calling update after every
+// poll. Temporary failures are tolerated for a few minutes. It returns the
+// finished build.
+func (c *Client) Follow(ctx context.Context, queueURL string, interval time.Duration, update func(Progress)) (*Build, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var executable *Executable
+	lastSuccess := time.Now()
+	for executable == nil {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		item, err := c.GetQueueItem(ctx, queueURL)
+		if tolerate(err, lastSuccess) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		lastSuccess = time.Now()
+		if item.Cancelled {
+			return nil, ErrQueueItemCancelled
+		}
+
+		update(Progress{Queue: item})
+		executable = item.Executable
+	}
+
+	return c.WatchBuild(ctx, executable.URL, interval, func(build *Build) {
+		update(Progress{Build: build})
+	})
+}
+
+// WatchBuild polls the build at buildURL until it finishes, calling update
+// after every successful poll. Temporary failures are tolerated for a few
+// minutes. It returns the finished build.
+func (c *Client) WatchBuild(ctx context.Context, buildURL string, interval time.Duration, update func(*Build)) (*Build, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastSuccess := time.Now()
+	for {
+		build, err := c.GetBuild(ctx, buildURL)
+		switch {
+		case tolerate(err, lastSuccess):
+		case err != nil:
+			return nil, err
+		default:
+			lastSuccess = time.Now()
+			update(build)
+			if !build.Building {
+				return build, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+type executor struct {
+	CurrentExecutable *Build `json:"currentExecutable"`
+}
+
+type computer struct {
+	Executors       []executor `json:"executors"`
+	OneOffExecutors []executor `json:"oneOffExecutors"`
+}
+
+// RunningBuilds lists the builds currently running on any node of the server.
+func (c *Client) RunningBuilds(ctx context.Context) ([]Build, error) {
+	tree := "computer[executors[currentExecutable[" + buildFullTree + "]],oneOffExecutors[currentExecutable[" + buildFullTree + "]]]"
+
+	var nodes struct {
+		Computers []computer `json:"computer"`
+	}
+	if err := c.getJSON(ctx, apiURL("/computer", tree), &nodes); err != nil {
+		return nil, err
+	}
+
+	// Pipeline builds show up both on a flyweight executor and on the
+	// executors running their node blocks.
+	seen := map[string]bool{}
+	var builds []Build
+	for _, node := range nodes.Computers {
+		for _, executor := range append(node.Executors, node.OneOffExecutors...) {
+			build := executor.CurrentExecutable
+			if build == nil || build.URL == "" || seen[build.URL] {
+				continue
+			}
+			seen[build.URL] = true
+			builds = append(builds, *build)
+		}
+	}
+	return builds, nil
+}
@@ -0,0 +1,90 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFollow(t *testing.T) {
+	var queuePolls, buildPolls atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/queue/item/7/api/json":
+			switch queuePolls.Add(1) {
+			case 1:
+				fmt.Fprint(w, `{"id":7,"why":"Waiting for next available executor"}`)
+			case 2:
+				http.Error(w, "restarting", http.StatusServiceUnavailable)
+			default:
+				fmt.Fprintf(w, `{"id":7,"executable":{"number":42,"url":"%s/job/a/42/"}}`, server.URL)
+			}
+		case "/queue/item/8/api/json":
+			fmt.Fprint(w, `{"id":8,"cancelled":true}`)
+		case "/job/a/42/api/json":
+			if buildPolls.Add(1) < 3 {
+				fmt.Fprint(w, `{"number":42,"building":true}`)
+				return
+			}
+			fmt.Fprint(w, `{"number":42,"building":false,"result":"SUCCESS"}`)
+		case "/job/a/lastBuild/api/json", "/job/a/43/api/json":
+			// A build started meanwhile by someone else.
+			fmt.Fprint(w, `{"number":43,"building":true}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "", "")
+	c.Retries = 0
+
+	var queued, builds []int
+	build, err := c.Follow(context.Background(), server.URL+"/queue/item/7/", time.Millisecond, func(p Progress) {
+		switch {
+		case p.Queue != nil:
+			queued = append(queued, p.Queue.ID)
+		case p.Build != nil:
+			builds = append(builds, p.Build.Number)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if build.Number != 42 || build.Building || build.Result != "SUCCESS" {
+		t.Errorf("Follow = %+v, want the finished build 42", build)
+	}
+	if fmt.Sprint(queued) != "[7 7]" || fmt.Sprint(builds) != "[42 42 42]" {
+		t.Errorf("reported the queue items %v and the builds %v", queued, builds)
+	}
+
+	_, err = c.Follow(context.Background(), server.URL+"/queue/item/8/", time.Millisecond, func(Progress) {})
+	if !errors.Is(err, ErrQueueItemCancelled) {
+		t.Errorf("Follow of a cancelled item returned %v, want ErrQueueItemCancelled", err)
+	}
+
+	_, err = c.Follow(context.Background(), server.URL+"/queue/item/9/", time.Millisecond, func(Progress) {})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Follow of a missing item returned %v, want ErrNotFound", err)
+	}
+}
+
+func TestFollowCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":7,"why":"Waiting for next available executor"}`)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := NewClient(server.URL, "", "")
+	_, err := c.Follow(ctx, server.URL+"/queue/item/7/", time.Millisecond, func(Progress) { cancel() })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Follow returned %v, want context.Canceled", err)
+	}
+}
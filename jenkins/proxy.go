@@ -0,0 +1,65 @@
+package jenkins
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Proxy routes the requests of a client through an HTTP, HTTPS or SOCKS5
+// proxy, e.g. an SSH tunnel opened with ssh -D 1080.
+type Proxy struct {
+	// URL of the proxy such as http://proxy:3128 or socks5://localhost:1080.
+	// An empty URL uses the proxy set in the environment, if any.
+	URL      string
+	Username string
+	Password string
+	// NoProxy lists the hosts reached directly, in the NO_PROXY format:
+	// comma separated host names, domain suffixes, IPs or CIDR ranges.
+	NoProxy string
+}
+
+// SetProxy applies the proxy settings to the requests of the client.
+func (c *Client) SetProxy(p Proxy) error {
+	transport := c.transport()
+	defer transport.CloseIdleConnections()
+
+	if p.URL == "" {
+		transport.Proxy = http.ProxyFromEnvironment
+		return nil
+	}
+
+	raw := p.URL
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return errors.New("jenkins: unsupported proxy scheme " + proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return errors.New("jenkins: proxy URL has no host")
+	}
+	if p.Username != "" {
+		proxyURL.User = url.UserPassword(p.Username, p.Password)
+	}
+
+	config := httpproxy.Config{
+		HTTPProxy:  proxyURL.String(),
+		HTTPSProxy: proxyURL.String(),
+		NoProxy:    p.NoProxy,
+	}
+	proxy := config.ProxyFunc()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultTimeout    = 30 * time.Second
+	DefaultRetries    = 3
+	DefaultRetryDelay = 500 * time.Millisecond
+
+	// outageTolerance is how long builds keep being monitored while the
+	// server cannot be reached.
+	outageTolerance = 5 * time.Minute
+)
+
+// IsTemporary tells if the request failing with err may succeed when tried
+// again, e.g. after a network hiccup or while Jenkins is restarting.
+func IsTemporary(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || IsUntrusted(err) {
+		return false
+	}
+
+	var status *StatusError
+	if errors.As(err, &status) {
+		switch status.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// tolerate tells if monitoring should keep polling after err, which is the
+// case for temporary failures as long as the server was reached recently.
+func tolerate(err error, lastSuccess time.Time) bool {
+	return IsTemporary(err) && time.Since(lastSuccess) < outageTolerance
+}
+
+// backoff returns the delay before the given retry, doubling every time and
+// randomised so that clients do not retry in lockstep.
+func (c *Client) backoff(retry int) time.Duration {
+	delay := c.RetryDelay << retry
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// Online tells if the last request reached the server.
+func (c *Client) Online() bool {
+	return !c.offline.Load()
+}
+
+// setOnline records whether the server answered a request, err being the
+// outcome of the request.
+func (c *Client) setOnline(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	online := err == nil || !IsTemporary(err)
+	if c.offline.Swap(!online) == !online {
+		return
+	}
+	if c.OnlineChanged != nil {
+		c.OnlineChanged(online)
+	}
+}
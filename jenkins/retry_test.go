@@ -0,0 +1,185 @@
+package jenkins
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIsTemporary(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"canceled", context.Canceled, false},
+		{"deadline", context.DeadlineExceeded, true},
+		{"unexpected EOF", io.ErrUnexpectedEOF, true},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"too many requests", &StatusError{StatusCode: http.StatusTooManyRequests}, true},
+		{"bad gateway", &StatusError{StatusCode: http.StatusBadGateway}, true},
+		{"unavailable", &StatusError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"gateway timeout", &StatusError{StatusCode: http.StatusGatewayTimeout}, true},
+		{"not found", &StatusError{StatusCode: http.StatusNotFound}, false},
+		{"unauthorized", &StatusError{StatusCode: http.StatusUnauthorized}, false},
+		{"internal error", &StatusError{StatusCode: http.StatusInternalServerError}, false},
+		{"wrapped", fmt.Errorf("fetching: %w", &StatusError{StatusCode: http.StatusServiceUnavailable}), true},
+		{"untrusted", &net.OpError{Op: "remote error", Err: x509.UnknownAuthorityError{}}, false},
+		{"other", errors.New("boom"), false},
+	}
+	for _, test := range tests {
+		if got := IsTemporary(test.err); got != test.want {
+			t.Errorf("%s: IsTemporary(%v) = %v, want %v", test.name, test.err, got, test.want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	c := &Client{RetryDelay: 100 * time.Millisecond}
+	for retry := range 5 {
+		delay := c.RetryDelay << retry
+		for range 20 {
+			got := c.backoff(retry)
+			if got < delay/2 || got > delay {
+				t.Errorf("backoff(%d) = %s, want between %s and %s", retry, got, delay/2, delay)
+			}
+		}
+	}
+}
+
+func TestTolerate(t *testing.T) {
+	unavailable := &StatusError{StatusCode: http.StatusServiceUnavailable}
+	tests := []struct {
+		name        string
+		err         error
+		lastSuccess time.Time
+		want        bool
+	}{
+		{"recent outage", unavailable, time.Now().Add(-time.Minute), true},
+		{"long outage", unavailable, time.Now().Add(-outageTolerance - time.Minute), false},
+		{"permanent error", &StatusError{StatusCode: http.StatusNotFound}, time.Now(), false},
+	}
+	for _, test := range tests {
+		if got := tolerate(test.err, test.lastSuccess); got != test.want {
+			t.Errorf("%s: tolerate = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestGetRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			http.Error(w, "restarting", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"name":"a","url":"/job/a/"}`)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "", "")
+	c.RetryDelay = time.Millisecond
+	var online []bool
+	c.OnlineChanged = func(o bool) { online = append(online, o) }
+
+	job, err := c.GetJob(context.Background(), "/job/a/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Name != "a" || requests.Load() != 3 {
+		t.Errorf("GetJob = %+v after %d requests, want job a after 3", job, requests.Load())
+	}
+	if fmt.Sprint(online) != "[false true]" {
+		t.Errorf("OnlineChanged was called with %v, want [false true]", online)
+	}
+	if !c.Online() {
+		t.Error("the client is offline after a successful request")
+	}
+}
+
+func TestGetGivesUp(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "restarting", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "", "")
+	c.Retries = 2
+	c.RetryDelay = time.Millisecond
+
+	if _, err := c.GetJob(context.Background(), "/job/a/"); !IsTemporary(err) {
+		t.Errorf("GetJob returned %v, want a temporary error", err)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("sent %d requests, want 3", requests.Load())
+	}
+	if c.Online() {
+		t.Error("the client is online while the server is unavailable")
+	}
+}
+
+func TestPostIsNotRetried(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posts.Add(1)
+			http.Error(w, "restarting", http.StatusServiceUnavailable)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "", "")
+	c.RetryDelay = time.Millisecond
+
+	if _, err := c.Build(context.Background(), "/job/a/"); err == nil {
+		t.Fatal("Build succeeded")
+	}
+	if posts.Load() != 1 {
+		t.Errorf("sent %d posts, want 1", posts.Load())
+	}
+}
+
+func TestPostTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			<-release
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := NewClient(server.URL, "", "")
+	c.Timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	for _, post := range []func() error{
+		func() error { _, err := c.Build(context.Background(), "/job/a/"); return err },
+		func() error {
+			_, err := c.BuildWithFiles(context.Background(), "/job/a/", nil, map[string]File{})
+			return err
+		},
+		func() error { return c.Stop(context.Background(), "/job/a/1/") },
+	} {
+		if err := post(); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("post returned %v, want a timeout", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("posts took %s", elapsed)
+	}
+}
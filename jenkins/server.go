@@ -0,0 +1,82 @@
+package jenkins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// NormalizeURL cleans up a server URL typed by the user: https is assumed
+// when no scheme is given, and trailing slashes or a pasted /api/json are
+// removed.
+func NormalizeURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", errors.New("jenkins: empty server URL")
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", errors.New("jenkins: unsupported scheme " + u.Scheme)
+	}
+	if u.Host == "" {
+		return "", errors.New("jenkins: server URL has no host")
+	}
+
+	u.Path = strings.TrimSuffix(strings.TrimRight(u.Path, "/"), "/api/json")
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath, u.RawQuery, u.Fragment = "", "", ""
+	return u.String(), nil
+}
+
+// User is the identity Jenkins sees for the credentials of the client.
+type User struct {
+	Name          string   `json:"name"`
+	Authenticated bool     `json:"authenticated"`
+	Anonymous     bool     `json:"anonymous"`
+	Authorities   []string `json:"authorities"`
+}
+
+// WhoAmI returns the user the client is authenticated as.
+func (c *Client) WhoAmI(ctx context.Context) (*User, error) {
+	user := &User{}
+	if err := c.getJSON(ctx, "/whoAmI/api/json", user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Server describes the Jenkins instance behind the base URL.
+type Server struct {
+	Version string
+	Mode    string `json:"mode"`
+	Desc    string `json:"nodeDescription"`
+}
+
+// GetServer returns the version and description of the server, which also
+// checks that the user is allowed to read it.
+func (c *Client) GetServer(ctx context.Context) (*Server, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	res, err := c.get(ctx, apiURL("", "mode,nodeDescription"))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	server := &Server{}
+	if err := json.NewDecoder(res.Body).Decode(server); err != nil {
+		return nil, err
+	}
+	server.Version = res.Header.Get("X-Jenkins")
+	return server, nil
+}
@@ -0,0 +1,71 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"jenkins.example.com", "https://jenkins.example.com", false},
+		{"  http://jenkins.example.com:8080/  ", "http://jenkins.example.com:8080", false},
+		{"https://example.com/jenkins/", "https://example.com/jenkins", false},
+		{"https://example.com/jenkins/api/json", "https://example.com/jenkins", false},
+		{"https://example.com/api/json/", "https://example.com", false},
+		{"https://example.com/jenkins/?foo=bar#top", "https://example.com/jenkins", false},
+		{"", "", true},
+		{"   ", "", true},
+		{"ftp://example.com", "", true},
+		{"https://", "", true},
+		{"http://exa mple.com", "", true},
+	}
+	for _, test := range tests {
+		got, err := NormalizeURL(test.raw)
+		if (err != nil) != test.wantErr {
+			t.Errorf("NormalizeURL(%q) error = %v, want error %v", test.raw, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("NormalizeURL(%q) = %q, want %q", test.raw, got, test.want)
+		}
+	}
+}
+
+func TestGetServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/whoAmI/api/json":
+			fmt.Fprint(w, `{"name":"alice","authenticated":true,"anonymous":false}`)
+		case "/api/json":
+			w.Header().Set("X-Jenkins", "2.462.3")
+			fmt.Fprint(w, `{"mode":"NORMAL","nodeDescription":"the built-in node"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "alice", "token")
+	user, err := c.WhoAmI(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "alice" || !user.Authenticated || user.Anonymous {
+		t.Errorf("WhoAmI = %+v", user)
+	}
+
+	info, err := c.GetServer(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != "2.462.3" || info.Mode != "NORMAL" || info.Desc != "the built-in node" {
+		t.Errorf("GetServer = %+v", info)
+	}
+}
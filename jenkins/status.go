@@ -0,0 +1,57 @@
+package jenkins
+
+import "strings"
+
+// Status is the state of a job as encoded by Jenkins in its ball color.
+type Status string
+
+const (
+	StatusSuccess  Status = "blue"
+	StatusFailed   Status = "red"
+	StatusUnstable Status = "yellow"
+	StatusAborted  Status = "aborted"
+	StatusDisabled Status = "disabled"
+	StatusNotBuilt Status = "notbuilt"
+	StatusPending  Status = "grey"
+)
+
+type HealthReport struct {
+	Score       int    `json:"score"`
+	Description string `json:"description"`
+}
+
+// Status returns the result of the last completed build of the job, and
+// whether a new build is running, which Jenkins marks with an _anime suffix.
+func (j *Job) Status() (Status, bool) {
+	color, running := strings.CutSuffix(j.Color, "_anime")
+	return Status(color), running
+}
+
+// Health returns the worst health report of the job, nil if there is none.
+func (j *Job) Health() *HealthReport {
+	var worst *HealthReport
+	for i, report := range j.HealthReport {
+		if worst == nil || report.Score < worst.Score {
+			worst = &j.HealthReport[i]
+		}
+	}
+	return worst
+}
+
+// Status returns the result of the build in the color encoding of jobs,
+// StatusPending while it is running.
+func (b *Build) Status() Status {
+	switch b.Result {
+	case "SUCCESS":
+		return StatusSuccess
+	case "FAILURE":
+		return StatusFailed
+	case "UNSTABLE":
+		return StatusUnstable
+	case "ABORTED":
+		return StatusAborted
+	case "NOT_BUILT":
+		return StatusNotBuilt
+	}
+	return StatusPending
+}
@@ -0,0 +1,117 @@
+package jenkins
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// Test case statuses as reported by the JUnit plugin.
+const (
+	TestPassed     = "PASSED"
+	TestFixed      = "FIXED"
+	TestSkipped    = "SKIPPED"
+	TestFailed     = "FAILED"
+	TestRegression = "REGRESSION"
+)
+
+type TestCase struct {
+	ClassName       string  `json:"className"`
+	Name            string  `json:"name"`
+	Status          string  `json:"status"`
+	Duration        float64 `json:"duration"`
+	ErrorDetails    string  `json:"errorDetails"`
+	ErrorStackTrace string  `json:"errorStackTrace"`
+	// FailedSince is the number of the build the test started failing in.
+	FailedSince int `json:"failedSince"`
+}
+
+// ID identifies the test across builds.
+func (t *TestCase) ID() string {
+	return t.ClassName + "." + t.Name
+}
+
+func (t *TestCase) Failed() bool {
+	return t.Status == TestFailed || t.Status == TestRegression
+}
+
+func (t *TestCase) Skipped() bool {
+	return t.Status == TestSkipped
+}
+
+type TestSuite struct {
+	Name  string     `json:"name"`
+	Cases []TestCase `json:"cases"`
+}
+
+// TestReport is the JUnit test result of a build.
+type TestReport struct {
+	PassCount int         `json:"passCount"`
+	FailCount int         `json:"failCount"`
+	SkipCount int         `json:"skipCount"`
+	Duration  float64     `json:"duration"`
+	Suites    []TestSuite `json:"suites"`
+}
+
+// Cases returns the test cases of every suite, keyed by ID.
+func (r *TestReport) Cases() map[string]TestCase {
+	cases := map[string]TestCase{}
+	for _, suite := range r.Suites {
+		for _, test := range suite.Cases {
+			cases[test.ID()] = test
+		}
+	}
+	return cases
+}
+
+const (
+	testCaseTree   = "className,name,status,duration,errorDetails,errorStackTrace,failedSince"
+	testReportTree = "passCount,failCount,skipCount,duration,suites[name,cases[" + testCaseTree + "]]"
+	// testStatusTree only fetches what the history of a test needs.
+	testStatusTree = "passCount,failCount,skipCount,suites[cases[className,name,status]]"
+)
+
+// TestReport returns the test results of a build, ErrNotFound when the build
+// did not publish any.
+func (c *Client) TestReport(ctx context.Context, buildURL string) (*TestReport, error) {
+	return c.testReport(ctx, buildURL, testReportTree)
+}
+
+// TestStatuses returns the test results of a build without their output.
+func (c *Client) TestStatuses(ctx context.Context, buildURL string) (*TestReport, error) {
+	return c.testReport(ctx, buildURL, testStatusTree)
+}
+
+func (c *Client) testReport(ctx context.Context, buildURL, tree string) (*TestReport, error) {
+	report := &TestReport{}
+	if err := c.getJSON(ctx, apiURL(strings.TrimRight(buildURL, "/")+"/testReport", tree), report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// TestDiff compares the test results of a build with the previous one.
+type TestDiff struct {
+	// NewFailures failed in the build but not in the previous one.
+	NewFailures []string
+	// Fixed failed in the previous build and passed in this one.
+	Fixed []string
+}
+
+// Diff compares the report with the one of a previous build.
+func (r *TestReport) Diff(previous *TestReport) TestDiff {
+	diff := TestDiff{}
+	before := previous.Cases()
+	for id, test := range r.Cases() {
+		old, existed := before[id]
+		switch {
+		case test.Failed() && (!existed || !old.Failed()):
+			diff.NewFailures = append(diff.NewFailures, id)
+		case !test.Failed() && !test.Skipped() && existed && old.Failed():
+			diff.Fixed = append(diff.Fixed, id)
+		}
+	}
+	slices.Sort(diff.NewFailures)
+	slices.Sort(diff.Fixed)
+	return diff
+}
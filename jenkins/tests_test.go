@@ -0,0 +1,78 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func newReport(statuses map[string]string) *TestReport {
+	suite := TestSuite{Name: "suite"}
+	for name, status := range statuses {
+		suite.Cases = append(suite.Cases, TestCase{ClassName: "pkg.Test", Name: name, Status: status})
+	}
+	return &TestReport{Suites: []TestSuite{suite}}
+}
+
+func TestReportDiff(t *testing.T) {
+	tests := []struct {
+		name        string
+		previous    map[string]string
+		current     map[string]string
+		newFailures []string
+		fixed       []string
+	}{
+		{"unchanged", map[string]string{"a": TestPassed, "b": TestFailed},
+			map[string]string{"a": TestPassed, "b": TestFailed}, nil, nil},
+		{"regressions", map[string]string{"b": TestPassed, "a": TestPassed},
+			map[string]string{"b": TestRegression, "a": TestFailed}, []string{"pkg.Test.a", "pkg.Test.b"}, nil},
+		{"fixed", map[string]string{"b": TestFailed, "a": TestRegression},
+			map[string]string{"b": TestFixed, "a": TestPassed}, nil, []string{"pkg.Test.a", "pkg.Test.b"}},
+		{"new failing test", map[string]string{},
+			map[string]string{"a": TestFailed}, []string{"pkg.Test.a"}, nil},
+		{"removed failing test", map[string]string{"a": TestFailed},
+			map[string]string{}, nil, nil},
+		{"skipped", map[string]string{"a": TestFailed, "b": TestSkipped},
+			map[string]string{"a": TestSkipped, "b": TestFailed}, []string{"pkg.Test.b"}, nil},
+	}
+	for _, test := range tests {
+		diff := newReport(test.current).Diff(newReport(test.previous))
+		if !slices.Equal(diff.NewFailures, test.newFailures) {
+			t.Errorf("%s: new failures %v, want %v", test.name, diff.NewFailures, test.newFailures)
+		}
+		if !slices.Equal(diff.Fixed, test.fixed) {
+			t.Errorf("%s: fixed %v, want %v", test.name, diff.Fixed, test.fixed)
+		}
+	}
+}
+
+func TestGetTestReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/a/1/testReport/api/json" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"passCount":1,"failCount":1,"suites":[{"name":"suite","cases":[
+			{"className":"pkg.Test","name":"a","status":"PASSED"},
+			{"className":"pkg.Test","name":"b","status":"REGRESSION","failedSince":1}]}]}`)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "", "")
+	got, err := c.TestReport(context.Background(), server.URL+"/job/a/1/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := got.Cases()
+	if b := cases["pkg.Test.b"]; len(cases) != 2 || !b.Failed() || b.FailedSince != 1 {
+		t.Errorf("TestReport has the cases %+v", cases)
+	}
+
+	if _, err := c.TestReport(context.Background(), server.URL+"/job/a/2/"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("TestReport of a build without tests returned %v, want ErrNotFound", err)
+	}
+}
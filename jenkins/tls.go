@@ -0,0 +1,142 @@
+package jenkins
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var ErrCertificateMismatch = errors.New("jenkins: server certificate does not match the pinned fingerprint")
+
+// TLS holds the settings needed to reach servers using a private CA, a self
+// signed certificate or requiring a client certificate. Certificates and
+// keys are PEM encoded.
+type TLS struct {
+	CA string
+	// Fingerprint pins the certificate of the server by its SHA-256
+	// fingerprint, which is then trusted whoever issued it.
+	Fingerprint string
+	Cert        string
+	Key         string
+}
+
+// Config returns the TLS client configuration for the settings.
+func (t TLS) Config() (*tls.Config, error) {
+	config := &tls.Config{}
+
+	if t.CA != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(t.CA)) {
+			return nil, errors.New("jenkins: no certificate found in the CA bundle")
+		}
+		config.RootCAs = pool
+	}
+
+	if t.Cert != "" || t.Key != "" {
+		cert, err := tls.X509KeyPair([]byte(t.Cert), []byte(t.Key))
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if t.Fingerprint != "" {
+		pinned := t.Fingerprint
+		config.InsecureSkipVerify = true
+		config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 || !strings.EqualFold(fingerprint(rawCerts[0]), pinned) {
+				return ErrCertificateMismatch
+			}
+			return nil
+		}
+	}
+
+	return config, nil
+}
+
+// SetTLS applies the TLS settings to the requests of the client.
+func (c *Client) SetTLS(t TLS) error {
+	config, err := t.Config()
+	if err != nil {
+		return err
+	}
+	transport := c.transport()
+	transport.TLSClientConfig = config
+	transport.CloseIdleConnections()
+	return nil
+}
+
+// Fingerprint returns the SHA-256 fingerprint of a certificate as colon
+// separated hex bytes, the way browsers show it.
+func Fingerprint(cert *x509.Certificate) string {
+	return fingerprint(cert.Raw)
+}
+
+func fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	hexSum := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	parts := make([]string, len(sum))
+	for i := range parts {
+		parts[i] = hexSum[i*2 : i*2+2]
+	}
+	return strings.Join(parts, ":")
+}
+
+// IsUntrusted tells if err comes from a server certificate which could not
+// be verified, e.g. self signed or issued by an unknown CA.
+func IsUntrusted(err error) bool {
+	var unknown x509.UnknownAuthorityError
+	var verification *tls.CertificateVerificationError
+	return errors.As(err, &unknown) || errors.As(err, &verification) || errors.Is(err, ErrCertificateMismatch)
+}
+
+// PeerCertificate connects to the server without verifying it and returns
+// the certificate it presents, so that the user can decide to trust it.
+func (c *Client) PeerCertificate(ctx context.Context) (*x509.Certificate, error) {
+	if !strings.HasPrefix(c.BaseURL, "https://") {
+		return nil, errors.New("jenkins: " + c.BaseURL + " does not use TLS")
+	}
+
+	transport := c.transport().Clone()
+	config := &tls.Config{InsecureSkipVerify: true}
+	if current := transport.TLSClientConfig; current != nil {
+		config.Certificates = current.Certificates
+	}
+	transport.TLSClientConfig = config
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.BaseURL+"/", nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+
+	if res.TLS == nil || len(res.TLS.PeerCertificates) == 0 {
+		return nil, errors.New("jenkins: server presented no certificate")
+	}
+	return res.TLS.PeerCertificates[0], nil
+}
+
+// transport returns the transport of the client, which NewClient sets up
+// as a copy of the default one so that it can be configured per client.
+func (c *Client) transport() *http.Transport {
+	if transport, ok := c.HTTP.Transport.(*http.Transport); ok {
+		return transport
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	c.HTTP.Transport = transport
+	return transport
+}
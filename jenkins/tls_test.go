@@ -0,0 +1,166 @@
+package jenkins
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTLSServer() *httptest.Server {
+	server := newUnstartedTLSServer()
+	server.StartTLS()
+	return server
+}
+
+// newUnstartedTLSServer does not log the handshakes failing on purpose.
+func newUnstartedTLSServer() *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"alice","authenticated":true}`)
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	return server
+}
+
+// newSelfSignedServer serves with a certificate of its own, httptest using
+// the same one for every server.
+func newSelfSignedServer(t *testing.T) *httptest.Server {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "other"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newUnstartedTLSServer()
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	server.StartTLS()
+	return server
+}
+
+func TestTLS(t *testing.T) {
+	server := newTLSServer()
+	defer server.Close()
+	other := newSelfSignedServer(t)
+	defer other.Close()
+
+	cert := server.Certificate()
+	ca := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	otherCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: other.Certificate().Raw}))
+
+	tests := []struct {
+		name     string
+		settings TLS
+		want     error
+	}{
+		{"untrusted", TLS{}, errUntrusted},
+		{"ca", TLS{CA: ca}, nil},
+		{"other ca", TLS{CA: otherCA}, errUntrusted},
+		{"pinned", TLS{Fingerprint: Fingerprint(cert)}, nil},
+		{"pinned lower case", TLS{Fingerprint: strings.ToLower(Fingerprint(cert))}, nil},
+		{"pinned other", TLS{Fingerprint: Fingerprint(other.Certificate())}, ErrCertificateMismatch},
+		{"pinned other with ca", TLS{CA: ca, Fingerprint: Fingerprint(other.Certificate())}, ErrCertificateMismatch},
+	}
+	for _, test := range tests {
+		c := NewClient(server.URL, "", "")
+		c.Retries = 0
+		if err := c.SetTLS(test.settings); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		_, err := c.WhoAmI(context.Background())
+		switch {
+		case test.want == nil && err != nil:
+			t.Errorf("%s: %v", test.name, err)
+		case test.want == errUntrusted && !IsUntrusted(err):
+			t.Errorf("%s: got %v, want an untrusted certificate", test.name, err)
+		case test.want == ErrCertificateMismatch && (!errors.Is(err, ErrCertificateMismatch) || !IsUntrusted(err)):
+			t.Errorf("%s: got %v, want a fingerprint mismatch", test.name, err)
+		}
+	}
+}
+
+// errUntrusted stands for any error of an unverified certificate.
+var errUntrusted = errors.New("untrusted")
+
+func TestTLSConfigErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings TLS
+	}{
+		{"no certificate in the bundle", TLS{CA: "not a certificate"}},
+		{"certificate without key", TLS{Cert: "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----\n"}},
+		{"key without certificate", TLS{Key: "key"}},
+	}
+	for _, test := range tests {
+		if _, err := test.settings.Config(); err == nil {
+			t.Errorf("%s: Config succeeded", test.name)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	server := newTLSServer()
+	defer server.Close()
+
+	got := Fingerprint(server.Certificate())
+	if len(got) != 32*3-1 || strings.Count(got, ":") != 31 || strings.ToUpper(got) != got {
+		t.Errorf("Fingerprint = %q, want 32 upper case hex bytes separated by colons", got)
+	}
+}
+
+func TestPeerCertificate(t *testing.T) {
+	server := newTLSServer()
+	defer server.Close()
+
+	c := NewClient(server.URL, "", "")
+	cert, err := c.PeerCertificate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cert.Raw, server.Certificate().Raw) {
+		t.Errorf("PeerCertificate returned %s, want the certificate of the server", cert.Subject)
+	}
+
+	// Trusting the certificate by its fingerprint lets the client connect.
+	if err := c.SetTLS(TLS{Fingerprint: Fingerprint(cert)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.WhoAmI(context.Background()); err != nil {
+		t.Errorf("WhoAmI with the pinned certificate: %v", err)
+	}
+
+	plain := NewClient("http://jenkins.example.com", "", "")
+	if _, err := plain.PeerCertificate(context.Background()); err == nil {
+		t.Error("PeerCertificate succeeded without TLS")
+	}
+}
@@ -0,0 +1,245 @@
+package jenkins
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	StringParameter   = "StringParameterDefinition"
+	BooleanParameter  = "BooleanParameterDefinition"
+	ChoiceParameter   = "ChoiceParameterDefinition"
+	TextParameter     = "TextParameterDefinition"
+	PasswordParameter = "PasswordParameterDefinition"
+	RunParameter      = "RunParameterDefinition"
+	FileParameter     = "FileParameterDefinition"
+)
+
+// Value is a parameter value of any JSON type kept as the text that is sent
+// back to Jenkins when launching a build.
+type Value string
+
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch value := value.(type) {
+	case nil:
+		*v = ""
+	case string:
+		*v = Value(value)
+	default:
+		*v = Value(fmt.Sprint(value))
+	}
+	return nil
+}
+
+func (v Value) String() string {
+	return string(v)
+}
+
+func (v Value) Bool() bool {
+	b, _ := strconv.ParseBool(string(v))
+	return b
+}
+
+type DefaultParameter struct {
+	Class string `json:"_class"`
+	Value Value  `json:"value"`
+}
+
+type Parameter struct {
+	Class   string           `json:"_class"`
+	Type    string           `json:"type"`
+	Name    string           `json:"name"`
+	Desc    string           `json:"description"`
+	Default DefaultParameter `json:"defaultParameterValue"`
+	Choices []string         `json:"choices"`
+	// ProjectName is the job whose builds a RunParameter refers to.
+	ProjectName string `json:"projectName"`
+}
+
+// Kind returns the parameter definition type, one of the *Parameter
+// constants for the parameters built into Jenkins.
+func (p *Parameter) Kind() string {
+	if p.Type != "" {
+		return p.Type
+	}
+	return p.Class[strings.LastIndex(p.Class, ".")+1:]
+}
+
+type Property struct {
+	Class      string      `json:"_class"`
+	Parameters []Parameter `json:"parameterDefinitions"`
+}
+
+type Job struct {
+	Class           string         `json:"_class"`
+	Name            string         `json:"name"`
+	DisplayName     string         `json:"displayName"`
+	FullName        string         `json:"fullName"`
+	URL             string         `json:"url"`
+	Color           string         `json:"color"`
+	Properties      []Property     `json:"property"`
+	Builds          []Build        `json:"builds"`
+	LastBuild       *Build         `json:"lastBuild"`
+	LastFailedBuild *Build         `json:"lastFailedBuild"`
+	HealthReport    []HealthReport `json:"healthReport"`
+	// Jobs are the children of folders and multibranch projects.
+	Jobs []Job `json:"jobs"`
+}
+
+var folderClasses = map[string]bool{
+	"com.cloudbees.hudson.plugins.folder.Folder":                            true,
+	"jenkins.branch.OrganizationFolder":                                     true,
+	"org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject": true,
+}
+
+// IsFolder tells if the job only contains other jobs, like folders,
+// organization folders and multibranch projects.
+func (j *Job) IsFolder() bool {
+	return folderClasses[j.Class]
+}
+
+// Title returns the name to show for the job. Branch jobs of multibranch
+// projects have their name URL encoded, e.g. feature%2Ffoo.
+func (j *Job) Title() string {
+	if j.DisplayName != "" {
+		return j.DisplayName
+	}
+	if name, err := url.PathUnescape(j.Name); err == nil {
+		return name
+	}
+	return j.Name
+}
+
+// Parameters returns the parameter definitions of the job, if it has any.
+func (j *Job) Parameters() []Parameter {
+	for _, property := range j.Properties {
+		if property.Class == "hudson.model.ParametersDefinitionProperty" {
+			return property.Parameters
+		}
+	}
+	return nil
+}
+
+type View struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	Jobs []Job  `json:"jobs"`
+}
+
+// Contains tells if the job is listed in the view.
+func (v *View) Contains(job Job) bool {
+	for _, candidate := range v.Jobs {
+		if candidate.URL == job.URL {
+			return true
+		}
+	}
+	return false
+}
+
+type State struct {
+	Jobs  []Job  `json:"jobs"`
+	Views []View `json:"views"`
+}
+
+type Build struct {
+	Number            int    `json:"number"`
+	FullDisplayName   string `json:"fullDisplayName"`
+	Building          bool   `json:"building"`
+	Result            string `json:"result,omitempty"`
+	URL               string `json:"url"`
+	Timestamp         int64  `json:"timestamp"`
+	Duration          int64  `json:"duration"`
+	EstimatedDuration int64  `json:"estimatedDuration"`
+	// Actions, ChangeSet and ChangeSets are only fetched with the history.
+	Actions    []Action    `json:"actions,omitempty"`
+	ChangeSet  ChangeSet   `json:"changeSet,omitzero"`
+	ChangeSets []ChangeSet `json:"changeSets,omitempty"`
+}
+
+func (b *Build) Started() time.Time {
+	return time.UnixMilli(b.Timestamp)
+}
+
+// Elapsed returns the duration of a finished build or the time the build
+// has been running so far.
+func (b *Build) Elapsed() time.Duration {
+	if !b.Building {
+		return time.Duration(b.Duration) * time.Millisecond
+	}
+	return time.Since(b.Started())
+}
+
+// Estimated returns the duration Jenkins expects the build to take, zero if
+// unknown.
+func (b *Build) Estimated() time.Duration {
+	if b.EstimatedDuration < 0 {
+		return 0
+	}
+	return time.Duration(b.EstimatedDuration) * time.Millisecond
+}
+
+// JobURL derives the URL of the job the build belongs to.
+func (b *Build) JobURL() string {
+	return strings.TrimSuffix(strings.TrimRight(b.URL, "/"), "/"+strconv.Itoa(b.Number)) + "/"
+}
+
+type Executable struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+}
+
+type Task struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type QueueItem struct {
+	ID           int         `json:"id"`
+	Task         Task        `json:"task"`
+	Why          string      `json:"why"`
+	Blocked      bool        `json:"blocked"`
+	Buildable    bool        `json:"buildable"`
+	Stuck        bool        `json:"stuck"`
+	Cancelled    bool        `json:"cancelled"`
+	InQueueSince int64       `json:"inQueueSince"`
+	Executable   *Executable `json:"executable"`
+}
+
+// Status describes why the item is still waiting in the queue.
+func (q *QueueItem) Status() string {
+	status := "In queue"
+	switch {
+	case q.Stuck:
+		status = "Stuck in queue"
+	case q.Blocked:
+		status = "Blocked in queue"
+	}
+	if q.Why != "" {
+		status += ": " + q.Why
+	}
+	return status
+}
+
+// JobPath returns the path of a job from its full name, e.g. folder/job
+// gives /job/folder/job/job/.
+func JobPath(fullName string) string {
+	return JobPathOf(strings.Split(strings.Trim(fullName, "/"), "/")...)
+}
+
+// JobPathOf returns the path of a job nested in the given folders, the last
+// name being the job itself.
+func JobPathOf(names ...string) string {
+	path := ""
+	for _, name := range names {
+		path += "/job/" + url.PathEscape(name)
+	}
+	return path + "/"
+}
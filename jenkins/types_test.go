@@ -0,0 +1,45 @@
+package jenkins
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParameterKind(t *testing.T) {
+	var job Job
+	err := json.Unmarshal([]byte(`{"property":[
+		{"_class":"hudson.model.JobProperty"},
+		{"_class":"hudson.model.ParametersDefinitionProperty","parameterDefinitions":[
+			{"_class":"hudson.model.StringParameterDefinition","name":"BRANCH"},
+			{"_class":"hudson.model.FileParameterDefinition","name":"CONFIG"},
+			{"_class":"hudson.model.BooleanParameterDefinition","type":"BooleanParameterDefinition","name":"DEBUG"},
+			{"_class":"io.jenkins.plugins.custom.CustomParameterDefinition","name":"CUSTOM"},
+			{"type":"ChoiceParameterDefinition","name":"ENV"},
+			{"name":"UNKNOWN"}
+		]}
+	]}`), &job)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		name string
+		kind string
+	}{
+		{"BRANCH", StringParameter},
+		{"CONFIG", FileParameter},
+		{"DEBUG", BooleanParameter},
+		{"CUSTOM", "CustomParameterDefinition"},
+		{"ENV", ChoiceParameter},
+		{"UNKNOWN", ""},
+	}
+	params := job.Parameters()
+	if len(params) != len(want) {
+		t.Fatalf("Parameters returned %d parameters, want %d", len(params), len(want))
+	}
+	for i, test := range want {
+		if params[i].Name != test.name || params[i].Kind() != test.kind {
+			t.Errorf("parameter %d = %s of kind %q, want %s of kind %q", i, params[i].Name, params[i].Kind(), test.name, test.kind)
+		}
+	}
+}
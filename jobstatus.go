@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+
+	"webservices/jenkins"
+)
+
+func statusIcon(job jenkins.Job) fyne.Resource {
+	if job.IsFolder() {
+		return theme.FolderIcon()
+	}
+
+	status, running := job.Status()
+	return statusResource(status, running)
+}
+
+func buildIcon(build jenkins.Build) fyne.Resource {
+	return statusResource(build.Status(), build.Building)
+}
+
+func statusResource(status jenkins.Status, running bool) fyne.Resource {
+	if running {
+		switch status {
+		case jenkins.StatusFailed:
+			return theme.NewErrorThemedResource(theme.MediaPlayIcon())
+		case jenkins.StatusUnstable:
+			return theme.NewWarningThemedResource(theme.MediaPlayIcon())
+		case jenkins.StatusSuccess:
+			return theme.NewSuccessThemedResource(theme.MediaPlayIcon())
+		}
+		return theme.NewPrimaryThemedResource(theme.MediaPlayIcon())
+	}
+
+	switch status {
+	case jenkins.StatusSuccess:
+		return theme.NewSuccessThemedResource(theme.ConfirmIcon())
+	case jenkins.StatusFailed:
+		return theme.NewErrorThemedResource(theme.CancelIcon())
+	case jenkins.StatusUnstable:
+		return theme.NewWarningThemedResource(theme.WarningIcon())
+	case jenkins.StatusAborted:
+		return theme.NewDisabledResource(theme.MediaStopIcon())
+	case jenkins.StatusDisabled:
+		return theme.NewDisabledResource(theme.VisibilityOffIcon())
+	}
+	return theme.NewDisabledResource(theme.QuestionIcon())
+}
+
+func timeAgo(t time.Time) string {
+	since := time.Since(t)
+	switch {
+	case since < time.Minute:
+		return "just now"
+	case since < time.Hour:
+		return fmt.Sprintf("%dm ago", int(since.Minutes()))
+	case since < time.Hour*24:
+		return fmt.Sprintf("%dh ago", int(since.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(since.Hours()/24))
+}
+
+// jobDetail summarizes the health and last build of a job.
+func jobDetail(job jenkins.Job) string {
+	var details []string
+	if job.IsFolder() {
+		details = append(details, "Folder")
+	}
+	if status, running := job.Status(); status == jenkins.StatusDisabled {
+		details = append(details, "Disabled")
+	} else if running {
+		details = append(details, "Running")
+	}
+	if health := job.Health(); health != nil {
+		details = append(details, fmt.Sprintf("Health %d%%", health.Score))
+	}
+	if build := job.LastBuild; build != nil {
+		last := fmt.Sprintf("#%d %s", build.Number, timeAgo(build.Started()))
+		if build.Result != "" {
+			last += " " + strings.ToLower(build.Result)
+		}
+		details = append(details, last)
+	}
+	return strings.Join(details, " · ")
+}
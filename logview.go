@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"webservices/jenkins"
+)
+
+// searchDelay debounces the search so that large logs are not filtered again
+// on every keystroke.
+const searchDelay = 300 * time.Millisecond
+
+// logViewer shows the lines of a log in a list, which only renders the
+// visible lines however large the log grows.
+type logViewer struct {
+	lines   []string
+	styled  [][]widget.RichTextSegment
+	shown   []int
+	partial string
+	state   ansiState
+
+	list   *widget.List
+	search *widget.Entry
+	follow *widget.Check
+	status *widget.Label
+	timer  *time.Timer
+}
+
+func (v *logViewer) matches(line string) bool {
+	query := strings.ToLower(v.search.Text)
+	return query == "" || strings.Contains(strings.ToLower(line), query)
+}
+
+func (v *logViewer) addLine(line string) {
+	line = strings.TrimSuffix(line, "\r")
+	segments := ansiSegments(line, &v.state)
+	plain := stripANSI(line)
+
+	v.lines = append(v.lines, plain)
+	v.styled = append(v.styled, segments)
+	if v.matches(plain) {
+		v.shown = append(v.shown, len(v.lines)-1)
+	}
+}
+
+func (v *logViewer) write(chunk string) {
+	lines := strings.Split(v.partial+chunk, "\n")
+	v.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		v.addLine(line)
+	}
+	v.refresh()
+}
+
+func (v *logViewer) flush() {
+	if v.partial != "" {
+		v.addLine(v.partial)
+		v.partial = ""
+	}
+	v.refresh()
+}
+
+func (v *logViewer) refresh() {
+	v.list.Refresh()
+	if v.follow.Checked {
+		v.list.ScrollToBottom()
+	}
+}
+
+// searchLater filters the log once the search text stopped changing.
+func (v *logViewer) searchLater() {
+	if v.timer != nil {
+		v.timer.Stop()
+	}
+	v.timer = time.AfterFunc(searchDelay, func() { fyne.Do(v.filter) })
+}
+
+func (v *logViewer) filter() {
+	v.shown = v.shown[:0]
+	for i, line := range v.lines {
+		if v.matches(line) {
+			v.shown = append(v.shown, i)
+		}
+	}
+	if v.search.Text != "" {
+		v.status.SetText(fmt.Sprintf("%d matching lines", len(v.shown)))
+	} else {
+		v.status.SetText(fmt.Sprintf("%d lines", len(v.lines)))
+	}
+	v.list.UnselectAll()
+	v.list.Refresh()
+	v.list.ScrollToTop()
+}
+
+func (v *logViewer) plainText() string {
+	return strings.Join(v.lines, "\n") + "\n"
+}
+
+// showLogViewer opens a window streaming the console output of the build.
+// Streaming stops when the window is closed.
+func showLogViewer(ctx context.Context, a fyne.App, client *jenkins.Client, title string, buildURL string) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	w := a.NewWindow("Console: " + title)
+
+	v := &logViewer{
+		search: widget.NewEntry(),
+		status: widget.NewLabel("Loading log..."),
+	}
+	v.list = widget.NewList(
+		func() int { return len(v.shown) },
+		func() fyne.CanvasObject {
+			line := widget.NewRichText()
+			line.Truncation = fyne.TextTruncateEllipsis
+			return line
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			line := item.(*widget.RichText)
+			line.Segments = v.styled[v.shown[id]]
+			line.Refresh()
+		},
+	)
+	// Long lines are cut to the width of the window, selecting one shows it
+	// whole.
+	v.list.OnSelected = func(id widget.ListItemID) {
+		v.list.Unselect(id)
+		line := widget.NewLabel(v.lines[v.shown[id]])
+		line.Wrapping = fyne.TextWrapBreak
+		line.Selectable = true
+		full := dialog.NewCustom(fmt.Sprintf("Line %d", v.shown[id]+1), "Close", line, w)
+		full.Resize(fyne.NewSize(w.Canvas().Size().Width*0.9, 0))
+		full.Show()
+	}
+	v.follow = widget.NewCheck("Follow", func(checked bool) {
+		if checked {
+			v.list.ScrollToBottom()
+		}
+	})
+	v.follow.Checked = true
+	w.SetOnClosed(func() {
+		cancel()
+		if v.timer != nil {
+			v.timer.Stop()
+		}
+	})
+	v.search.SetPlaceHolder("Search...")
+	v.search.OnChanged = func(string) { v.searchLater() }
+
+	copyButton := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
+		a.Clipboard().SetContent(v.plainText())
+		v.status.SetText("Log copied to clipboard")
+	})
+	saveButton := widget.NewButtonWithIcon("", theme.DocumentSaveIcon(), func() {
+		save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+
+			if _, err := writer.Write([]byte(v.plainText())); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			v.status.SetText("Log saved to " + writer.URI().Name())
+		}, w)
+		save.SetFileName(strings.ReplaceAll(title, " ", "_") + ".log")
+		save.Show()
+	})
+
+	toolbar := container.NewBorder(nil, nil, nil,
+		container.NewHBox(v.follow, copyButton, saveButton),
+		v.search,
+	)
+
+	w.SetContent(container.NewBorder(toolbar, v.status, nil, nil, v.list))
+	w.Resize(fyne.NewSize(800, 600))
+	w.Show()
+
+	go func() {
+		err := client.StreamLog(ctx, buildURL, time.Second*2, func(chunk string) {
+			fyne.Do(func() {
+				v.write(chunk)
+				if v.search.Text == "" {
+					v.status.SetText("Streaming log...")
+				}
+			})
+		})
+
+		fyne.Do(func() {
+			v.flush()
+			switch {
+			case errors.Is(err, context.Canceled):
+			case err != nil:
+				v.status.SetText("Error streaming log: " + err.Error())
+			default:
+				v.status.SetText(fmt.Sprintf("Build finished, %d lines", len(v.lines)))
+			}
+		})
+	}()
+}
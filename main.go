@@ -2,69 +2,26 @@ package main
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"hash/fnv"
-	"io"
-	"net/http"
+	"maps"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
-	"fyne.io/fyne/v2/data/binding"
-	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/driver/mobile"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
-)
-
-type DefaultParameter struct {
-	Class string `json:"_class"`
-	Value string `json:"value"`
-}
-
-type Parameter struct {
-	Name    string           `json:"name"`
-	Desc    string           `json:"description"`
-	Default DefaultParameter `json:"defaultParameterValue"`
-}
-
-type Property struct {
-	Class      string      `json:"_class"`
-	Parameters []Parameter `json:"parameterDefinitions"`
-}
 
-type Job struct {
-	Name       string     `json:"name"`
-	URL        string     `json:"url"`
-	Color      string     `json:"color"`
-	Properties []Property `json:"property"`
-}
-
-type State struct {
-	Jobs []Job `json:"jobs"`
-}
-
-type LastBuild struct {
-	Building bool   `json:"building"`
-	Result   string `json:"result,omitempty"`
-	URL      string `json:"url"`
-}
-
-type Executable struct {
-	Number int    `json:"number"`
-	URL    string `json:"url"`
-}
-
-type QueueItem struct {
-	Executable *Executable `json:"executable"`
-}
+	"webservices/jenkins"
+	"webservices/secrets"
+)
 
 type TouchableLabel struct {
 	widget.Label
@@ -121,39 +78,9 @@ func (b *TouchableLabel) TouchUp(e *mobile.TouchEvent) {
 func (b *TouchableLabel) TouchCancel(e *mobile.TouchEvent) {
 }
 
-func jenkinsRequest(method string, path string, data *url.Values) (*http.Response, error) {
-	prefs := fyne.CurrentApp().Preferences()
-
-	auth := prefs.String("username") + ":" + prefs.String("password")
-	basicAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
-
-	var reqData io.Reader
-	if data != nil {
-		reqData = strings.NewReader(data.Encode())
-	}
-
-	baseURL := prefs.String("url")
-	req, _ := http.NewRequest(method, baseURL+strings.Replace(path, baseURL, "", 1), reqData)
-	req.Header.Add("Authorization", basicAuth)
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-
-	client := &http.Client{}
-	return client.Do(req)
-}
-
-func parseBody[T any](req *http.Response, v *T) error {
-	bytes, _ := io.ReadAll(req.Body)
-	return json.Unmarshal(bytes, &v)
-}
-
-func TextToPositiveInt(s string) int {
-	h := fnv.New32a()
-	h.Write([]byte(s))
-	return int(h.Sum32() & 0x7fffffff) // mask sign bit
-}
-
 func main() {
-	var jobs []Job
+	var jobs []jenkins.Job
+	var folders []jenkins.Job
 	var list *widget.List
 
 	a := app.NewWithID("com.github.rontero.myaws")
@@ -163,315 +90,596 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	text := widget.NewLabel("My AWS")
-	hyperlink := widget.NewHyperlink("", nil)
-	hyperlink.Hide()
+	storageDir := a.Storage().RootURI().Path()
+	prefs := a.Preferences()
+	migrateProfiles(prefs)
+
+	store, err := secrets.Default(a.UniqueID(), storageDir)
+	var cache *secrets.Cache
+	if err != nil {
+		fmt.Println("Error opening secret store, tokens stay in the preferences: " + err.Error())
+		secretsPersistent = false
+	} else {
+		cache = secrets.NewCache(store)
+		cache.OnWritten = func(key, value string, err error) {
+			secretSaved(prefs, key, value, err)
+		}
+		secretStore = cache
+	}
+	jobCache = newOfflineCache(storageDir)
+
+	profileName := currentProfile(prefs)
+	clients := map[string]*jenkins.Client{}
+	// origins maps the URL of every listed job and folder to its profile.
+	origins := map[string]string{}
+	// rootJobs caches the top level jobs of each profile for quick switching.
+	rootJobs := map[string][]jenkins.Job{}
+
+	var updateConnectivity func()
+	clientOf := func(name string) *jenkins.Client {
+		if client, ok := clients[name]; ok {
+			return client
+		}
+		client := loadProfile(prefs, name).client()
+		client.OnlineChanged = func(bool) { fyne.Do(updateConnectivity) }
+		clients[name] = client
+		return client
+	}
+	clientFor := func(jobURL string) *jenkins.Client {
+		return clientOf(origins[jobURL])
+	}
+	activeProfiles := func() []string {
+		switch profileName {
+		case "":
+			return nil
+		case allProfiles:
+			return profileNames(prefs)
+		}
+		return []string{profileName}
+	}
+	// homeProfile is the profile used for server wide links.
+	homeProfile := func() profile {
+		if names := activeProfiles(); len(names) > 0 {
+			return loadProfile(prefs, names[0])
+		}
+		return profile{}
+	}
+
+	indicator := newConnectivity()
+	updateConnectivity = func() {
+		var offline []string
+		for _, name := range activeProfiles() {
+			if client, ok := clients[name]; ok && !client.Online() {
+				offline = append(offline, name)
+			}
+		}
+		indicator.update(offline)
+	}
 
-	flex := container.NewHBox(text, hyperlink)
+	var builds *buildsPanel
+	var runAction func(action buildAction)
+	var launchJob func(job jenkins.Job, request func() (string, error))
 
-	data := binding.BindStringList(&[]string{})
-	list = widget.NewListWithData(data,
+	text := widget.NewLabel("My AWS")
+	flex := container.NewHBox(text)
+
+	var visible []jenkins.Job
+	var applyFilter func()
+	var selectedURL string
+	var restoring bool
+
+	var fetchJobs func()
+	fetchButton := widget.NewButton("Fetch Jobs", func() { fetchJobs() })
+	historyButton := widget.NewButtonWithIcon("History", theme.HistoryIcon(), nil)
+	historyButton.Hide()
+	var views []jenkins.View
+	filter := jobFilter{Status: filterAll, Sort: sortServer}
+	favs := loadFavorites(prefs, profileNames(prefs))
+
+	list = widget.NewList(
+		func() int {
+			return len(visible)
+		},
 		func() fyne.CanvasObject {
-			return container.NewPadded(
-				NewTouchableLabel("template", func() {
-					fmt.Println("Tapped!")
-				}, func() {
-					fmt.Println("Hold")
-				}),
-				widget.NewIcon(theme.HomeIcon()),
+			detail := widget.NewLabel("")
+			detail.SizeName = theme.SizeNameCaptionText
+			detail.Importance = widget.LowImportance
+
+			return container.NewBorder(nil, nil, widget.NewIcon(theme.HomeIcon()), widget.NewIcon(nil),
+				container.NewVBox(
+					NewTouchableLabel("template", func() {
+						fmt.Println("Tapped!")
+					}, func() {
+						fmt.Println("Hold")
+					}),
+					detail,
+				),
 			)
 		},
-		func(i binding.DataItem, o fyne.CanvasObject) {
-			label := o.(*fyne.Container).Objects[0].(*TouchableLabel)
-			name, _ := i.(binding.String).Get()
-			label.Bind(i.(binding.String))
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			job := visible[id]
+			row := o.(*fyne.Container).Objects[0].(*fyne.Container)
+			label := row.Objects[0].(*TouchableLabel)
+			label.SetText(job.Title())
 
 			label.OnTapped = func() {
-				items, _ := data.Get()
-				for i, item := range items {
-					if item == label.Text {
-						list.Select(i)
-						break
-					}
-				}
+				list.Select(id)
 			}
 			label.OnHold = func() {
 				var popup *widget.PopUp
-				var url *url.URL
 
 				label.OnTapped()
 
-				for _, job := range jobs {
-					if job.Name == name {
-						url, _ = url.Parse(job.URL)
-						break
-					}
+				jobURL := job.URL
+				link, _ := url.Parse(jobURL)
+
+				items := container.NewVBox(widget.NewHyperlink("See '"+job.Title()+"' on Jenkins", link))
+
+				if !job.IsFolder() {
+					items.Add(widget.NewButtonWithIcon("Build history", theme.HistoryIcon(), func() {
+						popup.Hide()
+						showBuildHistory(ctx, a, clientFor(jobURL), job)
+					}))
 				}
 
-				popup = widget.NewModalPopUp(
-					container.NewVBox(
-						widget.NewHyperlink("See '"+name+"' on Jenkins", url),
-						widget.NewButton("Close", func() {
+				favoriteLabel, favoriteIcon := "Add to favorites", starOutlineIcon
+				if favs.Has(jobURL) {
+					favoriteLabel, favoriteIcon = "Remove from favorites", starIcon
+				}
+				items.Add(widget.NewButtonWithIcon(favoriteLabel, favoriteIcon, func() {
+					popup.Hide()
+					favs.Toggle(origins[jobURL], jobURL)
+					applyFilter()
+				}))
+
+				presets := loadPresets(prefs, jobURL)
+				for _, name := range presets.names() {
+					values := presets.Presets[name]
+					items.Add(widget.NewButtonWithIcon("Launch '"+name+"'", theme.MediaPlayIcon(), func() {
+						popup.Hide()
+
+						data := url.Values{}
+						for parameter, value := range values {
+							data.Set(parameter, value)
+						}
+						client := clientFor(jobURL)
+						launchJob(job, func() (string, error) {
+							return client.BuildWithParameters(ctx, jobURL, data)
+						})
+					}))
+				}
+				for _, row := range builds.Running(jobURL) {
+					for _, action := range row.current.actions(row.client) {
+						if row.current.build != nil {
+							action.Name = fmt.Sprintf("%s #%d", action.Name, row.current.build.Number)
+						}
+						items.Add(widget.NewButton(action.Name, func() {
 							popup.Hide()
-						}),
-					),
-					w.Canvas(),
-				)
+							runAction(action)
+						}))
+					}
+				}
+				items.Add(widget.NewButton("Close", func() {
+					popup.Hide()
+				}))
+
+				popup = widget.NewModalPopUp(items, w.Canvas())
 
 				popup.Resize(fyne.NewSize(200, 100))
 				popup.Show()
 			}
 
-			icons := []fyne.Resource{
-				theme.HomeIcon(),
-				theme.ComputerIcon(),
-				theme.AccountIcon(),
-				theme.DesktopIcon(),
-				theme.FileTextIcon(),
-				theme.FileApplicationIcon(),
-				theme.HistoryIcon(),
-				theme.GridIcon(),
-				theme.DocumentPrintIcon(),
-				theme.DocumentSaveIcon(),
+			icon := o.(*fyne.Container).Objects[1].(*widget.Icon)
+			icon.SetResource(statusIcon(job))
+			detail := row.Objects[1].(*widget.Label)
+			if profileName == allProfiles {
+				detail.SetText(strings.TrimSuffix(origins[job.URL]+" · "+jobDetail(job), " · "))
+			} else {
+				detail.SetText(jobDetail(job))
 			}
 
-			icon := o.(*fyne.Container).Objects[1].(*widget.Icon)
-			icon.SetResource(icons[TextToPositiveInt(name)%len(icons)])
+			star := o.(*fyne.Container).Objects[2].(*widget.Icon)
+			if favs.Has(job.URL) {
+				star.SetResource(starIcon)
+			} else {
+				star.SetResource(nil)
+			}
 		},
 	)
 
-	updateText := func(message string) {
-		fmt.Println(message)
-		fyne.Do(func() {
-			text.SetText(message)
-		})
+	// applyFilter recomputes the visible jobs keeping the selected job
+	// selected when it is still shown.
+	applyFilter = func() {
+		visible = filter.apply(jobs, views, favs)
+		list.Refresh()
+
+		for i, job := range visible {
+			if job.URL == selectedURL {
+				restoring = true
+				list.Select(i)
+				restoring = false
+				return
+			}
+		}
+
+		list.UnselectAll()
+		if selectedURL != "" {
+			selectedURL = ""
+			fetchButton.SetText("Fetch Jobs")
+			fetchButton.OnTapped = fetchJobs
+			historyButton.Hide()
+		}
 	}
 
-	fetchButton := widget.NewButton("Fetch Jobs", func() {
-		updateText("Fetching data...")
+	search := widget.NewEntry()
+	search.SetPlaceHolder("Search jobs...")
+	search.OnChanged = func(query string) {
+		filter.Query = query
+		applyFilter()
+	}
 
-		go func() {
-			res, err := jenkinsRequest("GET", "/api/json", nil)
-			if err != nil {
-				updateText("Error fetching data: " + err.Error())
-				return
-			}
-			defer res.Body.Close()
+	statusSelect := widget.NewSelect(statusFilters, func(status string) {
+		filter.Status = status
+		applyFilter()
+	})
+	statusSelect.SetSelected(filter.Status)
 
-			state := State{}
-			if err := parseBody(res, &state); err != nil {
-				updateText("Error parsing state response: " + err.Error())
-				return
-			}
+	viewSelect := widget.NewSelect(nil, func(view string) {
+		filter.View = view
+		applyFilter()
+	})
+	viewSelect.PlaceHolder = "All views"
 
-			jobs = state.Jobs
-			for _, user := range jobs {
-				data.Append(user.Name)
-			}
+	sortSelect := widget.NewSelect(sortOrders, func(order string) {
+		filter.Sort = order
+		applyFilter()
+	})
+	sortSelect.SetSelected(filter.Sort)
 
-			updateText("Tap a job to select it")
-		}()
+	favoritesOnly := widget.NewCheck("Favorites only", func(checked bool) {
+		filter.FavoritesOnly = checked
+		applyFilter()
 	})
 
-	launchJob := func(job Job, request func() (*http.Response, error)) {
-		updateText("Launching job: " + job.Name + "...")
-		res, err := request()
+	filterBar := container.NewVBox(
+		search,
+		container.NewHScroll(container.NewHBox(statusSelect, viewSelect, sortSelect, favoritesOnly)),
+	)
 
-		fyne.CurrentApp().SendNotification(&fyne.Notification{
-			Title:   "Job launched: " + job.Name,
-			Content: "Waiting for job to finish...",
+	updateText := func(message string) {
+		fmt.Println(message)
+		fyne.Do(func() {
+			text.SetText(message)
 		})
+	}
 
-		if err != nil {
-			updateText("Error launching job: " + err.Error())
-			return
+	breadcrumbs := container.NewHBox()
+
+	currentFolder := func() string {
+		if len(folders) > 0 {
+			return folders[len(folders)-1].URL
 		}
+		return ""
+	}
 
-		queueURL := res.Header.Get("Location")
-		ticker := time.NewTicker(time.Second * 2)
+	// sourcesOf returns the profiles listing the content of a folder.
+	sourcesOf := func(folderURL string) []string {
+		if folderURL != "" {
+			return []string{origins[folderURL]}
+		}
+		return activeProfiles()
+	}
 
-		defer ticker.Stop()
-		defer fyne.Do(func() {
-			fetchButton.Enable()
-		})
+	// loadJobs fetches the jobs of the current folder and merges them into
+	// the list, keeping the selection and scroll position. Servers which
+	// cannot be reached are listed with the jobs last fetched from them.
+	loadJobs := func(quiet bool) {
+		if !quiet {
+			updateText("Fetching data...")
+		}
 
-		for {
-			select {
-			case <-ctx.Done():
-				fmt.Println("Cancelled")
+		folderURL := currentFolder()
+		sources := sourcesOf(folderURL)
+		if len(sources) == 0 {
+			updateText("Set up a Jenkins server first")
+			return
+		}
+
+		sourceClients := make([]*jenkins.Client, len(sources))
+		for i, name := range sources {
+			sourceClients[i] = clientOf(name)
+		}
+		shownProfile := profileName
+
+		go func() {
+			states := make([]*jenkins.State, len(sources))
+			errs := make([]error, len(sources))
+
+			var wg sync.WaitGroup
+			for i, client := range sourceClients {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					states[i], errs[i] = client.GetState(ctx, folderURL)
+				}()
+			}
+			wg.Wait()
+
+			state := &jenkins.State{}
+			found := map[string]string{}
+			var failures, stale []string
+			for i, name := range sources {
+				if errs[i] != nil {
+					cached, ok := jobCache.state(name, folderURL)
+					if !ok {
+						failures = append(failures, name+": "+errs[i].Error())
+						continue
+					}
+					states[i] = &cached.State
+					stale = append(stale, name+" from "+timeAgo(cached.Fetched))
+				} else {
+					jobCache.saveState(name, folderURL, states[i])
+				}
+				for _, job := range states[i].Jobs {
+					found[job.URL] = name
+				}
+				state.Jobs = append(state.Jobs, states[i].Jobs...)
+				state.Views = append(state.Views, states[i].Views...)
+			}
+			if len(failures) == len(sources) {
+				updateText("Error fetching data: " + strings.Join(failures, "; "))
 				return
-			case <-ticker.C:
-				fmt.Println("Checking job status...")
+			}
+			if len(failures) > 0 {
+				updateText("Error fetching data from " + strings.Join(failures, "; "))
+			}
+			status := ""
+			if len(stale) > 0 {
+				status = "Offline, showing data of " + strings.Join(stale, ", ")
+			}
 
-				res, err := jenkinsRequest("GET", queueURL+"api/json", nil)
-				if err != nil {
-					updateText("Error fetching queue status: " + err.Error())
-					return
+			// Views of the same name on several servers are listed once.
+			var viewNames []string
+			for _, view := range state.Views {
+				if !slices.Contains(viewNames, view.Name) {
+					viewNames = append(viewNames, view.Name)
 				}
+			}
 
-				queueItem := QueueItem{}
-				if err := parseBody(res, &queueItem); err != nil {
-					updateText("Error parsing queue response: " + err.Error())
+			fyne.Do(func() {
+				if folderURL != currentFolder() || shownProfile != profileName {
 					return
 				}
 
-				if queueItem.Executable == nil {
-					updateText("Job in queue...")
-					continue
+				maps.Copy(origins, found)
+				if folderURL == "" {
+					rootJobs[profileName] = state.Jobs
 				}
 
-				res, err = jenkinsRequest("GET", job.URL+"lastBuild/api/json", nil)
-				if err != nil {
-					updateText("Error fetching job status: " + err.Error())
-					return
+				diff := diffJobs(jobs, state.Jobs)
+				first := jobs == nil
+				jobs = state.Jobs
+				views = state.Views
+				viewSelect.SetOptions(viewNames)
+				if !slices.Contains(viewNames, filter.View) {
+					viewSelect.ClearSelected()
+					filter.View = ""
 				}
-				defer res.Body.Close()
 
-				lastBuild := LastBuild{}
-				if err := parseBody(res, &lastBuild); err != nil {
-					updateText("Error parsing build response: " + err.Error())
-					return
+				switch {
+				case status != "":
+					offset := list.GetScrollOffset()
+					applyFilter()
+					list.ScrollToOffset(offset)
+					text.SetText(status)
+				case first:
+					applyFilter()
+					text.SetText("Tap a job to select it")
+				case !diff.empty():
+					offset := list.GetScrollOffset()
+					applyFilter()
+					list.ScrollToOffset(offset)
+					text.SetText(diff.String())
+				case !quiet:
+					text.SetText(diff.String())
 				}
+			})
+		}()
+	}
 
-				if lastBuild.Building {
-					updateText("Building...")
-					continue
-				} else {
-					fyne.Do(func() {
-						text.SetText("Build finished with " + lastBuild.Result)
-						hyperlink.SetText("See output")
-						hyperlink.SetURLFromString(lastBuild.URL + "consoleText")
-						hyperlink.Show()
-					})
-					fyne.CurrentApp().SendNotification(&fyne.Notification{
-						Title:   "Build finished with " + lastBuild.Result,
-						Content: lastBuild.URL + "console",
-					})
-					return
+	fetchJobs = func() {
+		loadJobs(false)
+	}
+
+	var openFolder func(depth int)
+	openFolder = func(depth int) {
+		folders = folders[:depth]
+
+		breadcrumbs.RemoveAll()
+		root := widget.NewButtonWithIcon("Jenkins", theme.HomeIcon(), nil)
+		breadcrumbs.Add(root)
+		buttons := []*widget.Button{root}
+		for _, folder := range folders {
+			breadcrumbs.Add(widget.NewLabel("›"))
+			button := widget.NewButtonWithIcon(folder.Title(), theme.FolderOpenIcon(), nil)
+			breadcrumbs.Add(button)
+			buttons = append(buttons, button)
+		}
+		for i, button := range buttons {
+			button.Importance = widget.LowImportance
+			button.OnTapped = func() { openFolder(i) }
+		}
+
+		historyButton.Hide()
+		jobs = nil
+		if depth == 0 {
+			jobs = rootJobs[profileName]
+		}
+		if jobs == nil {
+			// Show the jobs fetched last time until the servers answer.
+			folderURL := currentFolder()
+			for _, name := range sourcesOf(folderURL) {
+				if cached, ok := jobCache.state(name, folderURL); ok {
+					for _, job := range cached.State.Jobs {
+						origins[job.URL] = name
+					}
+					jobs = append(jobs, cached.State.Jobs...)
 				}
 			}
 		}
+		selectedURL = ""
+		applyFilter()
+		list.ScrollToTop()
+		fetchJobs()
 	}
 
-	list.OnSelected = func(i widget.ListItemID) {
-		text.SetText("Job: " + jobs[i].Name)
-		fetchButton.SetText("Launch Job")
+	launchJob = func(job jenkins.Job, request func() (string, error)) {
+		updateText("Launched " + job.Name + ", see Builds")
+		builds.Launch(clientFor(job.URL), job, request)
+	}
 
+	runAction = func(action buildAction) {
+		updateText(action.Name + "...")
 		go func() {
-			res, err := jenkinsRequest("GET", "/job/"+jobs[i].Name+"/api/json", nil)
-			if err != nil {
-				updateText("Error fetching job properties: " + err.Error())
-				return
-			}
-
-			job := Job{}
-			if err := parseBody(res, &job); err != nil {
-				updateText("Error parsing job properties: " + err.Error())
+			if err := action.Run(ctx); err != nil {
+				updateText(action.Name + " failed: " + err.Error())
 				return
 			}
+			updateText(action.Name + " requested")
+		}()
+	}
 
-			var parameters []Parameter
-			if len(job.Properties) > 1 {
-				for _, property := range job.Properties {
-					if property.Class == "hudson.model.ParametersDefinitionProperty" {
-						parameters = property.Parameters
-						break
-					}
-				}
-			}
-
-			if len(parameters) > 0 {
-				fyne.Do(func() { fetchButton.SetText("Launch Job With Parameters") })
-				fetchButton.OnTapped = func() {
-					fetchButton.Disable()
-					hyperlink.Hide()
+	builds = newBuildsPanel(ctx, a, w, func() []*jenkins.Client {
+		var active []*jenkins.Client
+		for _, name := range activeProfiles() {
+			active = append(active, clientOf(name))
+		}
+		return active
+	}, runAction)
 
-					widgets := make([]*widget.FormItem, len(parameters))
-					entries := make([]*widget.Entry, len(parameters))
-					for i, parameter := range parameters {
-						entry := widget.NewEntry()
-						entry.SetText(parameter.Default.Value)
+	list.OnSelected = func(i widget.ListItemID) {
+		if restoring {
+			return
+		}
 
-						item := widget.NewFormItem(parameter.Name, entry)
-						item.HintText = parameter.Desc
+		selected := visible[i]
+		if selected.IsFolder() {
+			folders = append(folders, selected)
+			openFolder(len(folders))
+			return
+		}
+		selectedURL = selected.URL
 
-						widgets[i] = item
-						entries[i] = entry
-					}
+		text.SetText("Job: " + selected.Title())
+		fetchButton.SetText("Launch Job")
+		// Nothing is launched until the parameters of the job are known.
+		fetchButton.OnTapped = nil
 
-					dialog.ShowForm("Job properties", "Launch", "Cancel",
-						widgets,
-						func(accept bool) {
-							if accept {
-								go launchJob(jobs[i], func() (*http.Response, error) {
-									data := url.Values{}
-									for i, entry := range entries {
-										data.Add(parameters[i].Name, entry.Text)
-									}
-									return jenkinsRequest("POST", jobs[i].URL+"buildWithParameters", &data)
-								})
-							} else {
-								fetchButton.Enable()
-							}
-						}, w,
-					)
+		client := clientFor(selected.URL)
+		origin := origins[selected.URL]
+		historyButton.OnTapped = func() { showBuildHistory(ctx, a, client, selected) }
+		historyButton.Show()
+		go func() {
+			job, err := client.GetJob(ctx, selected.URL)
+			if err != nil {
+				cached, ok := jobCache.job(origin, selected.URL)
+				if !ok {
+					updateText("Error fetching job properties: " + err.Error())
+					return
 				}
+				updateText("Offline, using the job properties from " + timeAgo(cached.Fetched))
+				job = &cached.Job
 			} else {
-				fetchButton.OnTapped = func() {
-					fetchButton.Disable()
-					hyperlink.Hide()
-					go launchJob(jobs[i], func() (*http.Response, error) {
-						return jenkinsRequest("POST", jobs[i].URL+"build", nil)
-					})
-				}
+				jobCache.saveJob(origin, job)
 			}
 
+			parameters := job.Parameters()
+			fyne.Do(func() {
+				// The user may have selected another job in the meantime.
+				if selectedURL != selected.URL {
+					return
+				}
+				if len(parameters) > 0 {
+					fetchButton.SetText("Launch Job With Parameters")
+					fetchButton.OnTapped = func() {
+						showParametersDialog(ctx, client, prefs, selected, parameters, w, func(request func() (string, error)) {
+							launchJob(selected, request)
+						})
+					}
+				} else {
+					fetchButton.OnTapped = func() {
+						launchJob(selected, func() (string, error) {
+							return client.Build(ctx, selected.URL)
+						})
+					}
+				}
+			})
 		}()
 	}
 
+	profileSelect := widget.NewSelect(nil, nil)
+	profileSelect.PlaceHolder = "No server"
+
+	refreshProfiles := func() {
+		names := profileNames(prefs)
+		if len(names) > 1 {
+			names = append(names, allProfiles)
+		}
+		profileSelect.SetOptions(names)
+		profileSelect.SetSelected(profileName)
+		favs = loadFavorites(prefs, profileNames(prefs))
+	}
+
+	profileSelect.OnChanged = func(name string) {
+		if name == profileName {
+			return
+		}
+		profileName = name
+		prefs.SetString("profile", name)
+		updateConnectivity()
+		openFolder(0)
+	}
+
+	onProfileChanged := func(name string) {
+		clear(clients)
+		clear(rootJobs)
+		profileName = name
+		if name == "" {
+			profileName = currentProfile(prefs)
+		}
+		prefs.SetString("profile", profileName)
+		refreshProfiles()
+		updateConnectivity()
+		openFolder(0)
+	}
+
 	setUpButton := widget.NewButton("Set up", func() {
-		urlEntry := widget.NewEntry()
-		urlEntry.SetText(a.Preferences().String("url"))
-
-		nameEntry := widget.NewEntry()
-		nameEntry.SetText(a.Preferences().String("username"))
-
-		passwordEntry := widget.NewPasswordEntry()
-		passwordEntry.SetText(a.Preferences().String("password"))
-
-		dialog.ShowForm("Setup Jenkins API", "Save", "Discard",
-			[]*widget.FormItem{
-				widget.NewFormItem("Jenkins URL", urlEntry),
-				widget.NewFormItem("Username", nameEntry),
-				widget.NewFormItem("User Token", passwordEntry),
-			},
-			func(accept bool) {
-				if accept {
-					app := fyne.CurrentApp()
-					pref := app.Preferences()
-
-					pref.SetString("url", urlEntry.Text)
-					pref.SetString("username", nameEntry.Text)
-					pref.SetString("password", passwordEntry.Text)
-
-					app.SendNotification(&fyne.Notification{
-						Title:   "Settings saved!",
-						Content: "Jenkins URL: " + urlEntry.Text,
-					})
-				}
-			}, w,
-		)
+		current := profile{}
+		if profileName != allProfiles {
+			current = loadProfile(prefs, profileName)
+		}
+		showProfileDialog(w, prefs, current, onProfileChanged)
 	})
 
+	newProfileButton := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() {
+		showProfileDialog(w, prefs, profile{}, onProfileChanged)
+	})
+
+	refreshProfiles()
+
 	actionbar := container.NewScroll(container.NewHBox(
 		flex,
 		fetchButton,
+		historyButton,
+		profileSelect,
 		setUpButton,
+		newProfileButton,
+		indicator.content,
 	))
 	actionbar.Direction = container.ScrollHorizontalOnly
 
 	openJenkins := func(path string) {
-		baseURL := a.Preferences().String("url")
+		baseURL := homeProfile().URL
 		url, err := url.Parse(baseURL + path)
 		if err != nil {
 			updateText("Error parsing URL: " + err.Error())
@@ -481,7 +689,7 @@ func main() {
 	}
 
 	openPort := func(port int) {
-		baseURL := a.Preferences().String("url")
+		baseURL := homeProfile().URL
 		baseURL = regexp.MustCompile(`:[0-9]+$`).ReplaceAllString(baseURL, fmt.Sprintf(":%d", port))
 		url, err := url.Parse(baseURL)
 		if err != nil {
@@ -491,6 +699,11 @@ func main() {
 		a.OpenURL(url)
 	}
 
+	pull := newPullToRefresh(fetchJobs)
+	if !fyne.CurrentDevice().IsMobile() {
+		pull.Hide()
+	}
+
 	content := container.NewBorder(
 		actionbar,
 		widget.NewToolbar(
@@ -502,8 +715,8 @@ func main() {
 			widget.NewToolbarAction(theme.SettingsIcon(), func() { openJenkins("/manage") }),
 			widget.NewToolbarAction(theme.HelpIcon(), func() {
 				var popup *widget.PopUp
-				userURL := a.Preferences().String("url")
-				user := a.Preferences().String("username")
+				userURL := homeProfile().URL
+				user := homeProfile().Username
 
 				fullURL := userURL + "/user/" + user + "/security/"
 				link := widget.NewHyperlink(fullURL, nil)
@@ -522,6 +735,8 @@ func main() {
 					container.NewVBox(
 						title, widget.NewLabel(`
 If you are using a VPN, make sure to connect to it first.
+Servers behind an HTTP proxy or an SSH SOCKS tunnel can be
+reached by setting the proxy of the profile.
 
 First you will have to set up your Jenkins URL in the settings.
 Also you will need to create an user token in Jenkins.
@@ -538,14 +753,63 @@ Note: If you add the url and username in the settings, you can come here and cli
 				popup.Show()
 			}),
 		), nil, nil,
-		list,
+		container.NewAppTabs(
+			container.NewTabItemWithIcon("Jobs", theme.ListIcon(), container.NewBorder(
+				container.NewVBox(container.NewHScroll(breadcrumbs), filterBar, pull), nil, nil, nil,
+				list,
+			)),
+			container.NewTabItemWithIcon("Builds", theme.MediaPlayIcon(), builds.Content()),
+		),
 	)
 
+	// The profiles can only be used once their secrets are read, which may
+	// wait for the user to unlock the keyring.
+	loading := []fyne.Disableable{fetchButton, profileSelect, setUpButton, newProfileButton}
+	for _, item := range loading {
+		item.Disable()
+	}
+	updateText("Reading the server credentials...")
 	go func() {
-		fetchButton.Tapped(nil)
+		if cache != nil {
+			loadSecrets(prefs, store, cache)
+		}
+		fyne.Do(func() {
+			for _, item := range loading {
+				item.Enable()
+			}
+			clear(clients)
+			openFolder(0)
+		})
+	}()
+
+	paused := false
+	a.Lifecycle().SetOnExitedForeground(func() { paused = true })
+	a.Lifecycle().SetOnEnteredForeground(func() { paused = false })
+
+	go func() {
+		ticker := time.NewTicker(time.Second * 30)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			fyne.Do(func() {
+				if !paused {
+					loadJobs(true)
+				}
+			})
+		}
 	}()
 
 	fmt.Println("Starting app...")
 	w.SetContent(content)
 	w.ShowAndRun()
+
+	if cache != nil {
+		cache.Flush()
+	}
 }
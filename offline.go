@@ -0,0 +1,124 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"webservices/jenkins"
+)
+
+// offlineCache persists what was last fetched from each profile in the app
+// storage, so that the app opens with data while the servers are out of
+// reach, e.g. before connecting to the VPN.
+type offlineCache struct {
+	dir string
+}
+
+// jobCache is set up in main before any profile is loaded.
+var jobCache *offlineCache
+
+type cachedState struct {
+	Fetched time.Time     `json:"fetched"`
+	State   jenkins.State `json:"state"`
+}
+
+type cachedJob struct {
+	Fetched time.Time   `json:"fetched"`
+	Job     jenkins.Job `json:"job"`
+}
+
+func newOfflineCache(dir string) *offlineCache {
+	return &offlineCache{dir: filepath.Join(dir, "cache")}
+}
+
+// path returns the file caching the object at objectURL for the profile.
+func (c *offlineCache) path(profile, kind, objectURL string) string {
+	sum := sha256.Sum256([]byte(objectURL))
+	name := kind + "-" + hex.EncodeToString(sum[:8]) + ".json"
+	return filepath.Join(c.dir, url.PathEscape(profile), name)
+}
+
+func (c *offlineCache) load(path string, v any) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		fmt.Println("Error reading offline cache: " + err.Error())
+		return false
+	}
+	return true
+}
+
+func (c *offlineCache) save(path string, v any) {
+	data, err := json.Marshal(v)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0o700)
+	}
+	if err == nil {
+		err = writeFileAtomic(path, data)
+	}
+	if err != nil {
+		fmt.Println("Error writing offline cache: " + err.Error())
+	}
+}
+
+// writeFileAtomic writes data to a temporary file of its own which is then
+// renamed to path, so that concurrent refreshes or a crash never leave a
+// truncated or interleaved file behind.
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
+
+// state returns the jobs and views last fetched from a folder, the top
+// level when folderURL is empty.
+func (c *offlineCache) state(profile, folderURL string) (cachedState, bool) {
+	var cached cachedState
+	ok := c.load(c.path(profile, "state", folderURL), &cached)
+	return cached, ok
+}
+
+func (c *offlineCache) saveState(profile, folderURL string, state *jenkins.State) {
+	c.save(c.path(profile, "state", folderURL), cachedState{Fetched: time.Now(), State: *state})
+}
+
+// job returns the details last fetched for a job, along with its builds.
+func (c *offlineCache) job(profile, jobURL string) (cachedJob, bool) {
+	var cached cachedJob
+	ok := c.load(c.path(profile, "job", jobURL), &cached)
+	return cached, ok
+}
+
+func (c *offlineCache) saveJob(profile string, job *jenkins.Job) {
+	c.save(c.path(profile, "job", job.URL), cachedJob{Fetched: time.Now(), Job: *job})
+}
+
+// drop forgets everything cached for the profile.
+func (c *offlineCache) drop(profile string) {
+	if profile == "" {
+		return
+	}
+	if err := os.RemoveAll(filepath.Join(c.dir, url.PathEscape(profile))); err != nil {
+		fmt.Println("Error clearing offline cache: " + err.Error())
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"webservices/jenkins"
+)
+
+func TestOfflineCache(t *testing.T) {
+	c := newOfflineCache(t.TempDir())
+
+	if _, ok := c.state("dev", ""); ok {
+		t.Error("an empty cache has a state")
+	}
+	c.saveState("dev", "", &jenkins.State{Jobs: []jenkins.Job{{Name: "a"}}})
+	c.saveState("dev", "https://jenkins/job/folder/", &jenkins.State{Jobs: []jenkins.Job{{Name: "b"}}})
+	c.saveJob("dev", &jenkins.Job{Name: "a", URL: "https://jenkins/job/a/"})
+
+	if cached, ok := c.state("dev", ""); !ok || len(cached.State.Jobs) != 1 || cached.State.Jobs[0].Name != "a" || cached.Fetched.IsZero() {
+		t.Errorf("state = %+v, %v, want the top level jobs", cached, ok)
+	}
+	if cached, ok := c.state("dev", "https://jenkins/job/folder/"); !ok || cached.State.Jobs[0].Name != "b" {
+		t.Errorf("state of the folder = %+v, %v", cached, ok)
+	}
+	if cached, ok := c.job("dev", "https://jenkins/job/a/"); !ok || cached.Job.Name != "a" {
+		t.Errorf("job = %+v, %v", cached, ok)
+	}
+	if _, ok := c.state("prod", ""); ok {
+		t.Error("the profiles share their cache")
+	}
+
+	c.drop("dev")
+	if _, ok := c.state("dev", ""); ok {
+		t.Error("the state is cached after drop")
+	}
+}
+
+func TestOfflineCacheCorrupted(t *testing.T) {
+	c := newOfflineCache(t.TempDir())
+	path := c.path("dev", "state", "")
+	os.MkdirAll(filepath.Dir(path), 0o700)
+	os.WriteFile(path, []byte(`{"fetched":`), 0o600)
+
+	if _, ok := c.state("dev", ""); ok {
+		t.Error("a truncated file was loaded")
+	}
+}
+
+func TestOfflineCacheConcurrentSaves(t *testing.T) {
+	c := newOfflineCache(t.TempDir())
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			jobs := make([]jenkins.Job, 200+i*10)
+			for j := range jobs {
+				jobs[j].Name = fmt.Sprintf("job-%d-%d", i, j)
+			}
+			c.saveState("dev", "", &jenkins.State{Jobs: jobs})
+		}()
+	}
+	wg.Wait()
+
+	cached, ok := c.state("dev", "")
+	if !ok {
+		t.Fatal("concurrent saves left an unreadable file")
+	}
+	// The file is one of the states saved, whole.
+	i := (len(cached.State.Jobs) - 200) / 10
+	if last := cached.State.Jobs[len(cached.State.Jobs)-1].Name; last != fmt.Sprintf("job-%d-%d", i, len(cached.State.Jobs)-1) {
+		t.Errorf("the cached state mixes several saves, ending with %s", last)
+	}
+	files, _ := os.ReadDir(filepath.Dir(c.path("dev", "state", "")))
+	if len(files) != 1 {
+		t.Errorf("the cache has %d files, want no temporary file left", len(files))
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"webservices/jenkins"
+)
+
+// parameterInput is the form widget used to fill in a job parameter.
+type parameterInput struct {
+	parameter jenkins.Parameter
+	widget    fyne.CanvasObject
+	value     func() string
+	set       func(value string)
+	// file is set once a file has been picked for a file parameter.
+	file *jenkins.File
+}
+
+func newParameterInput(ctx context.Context, client *jenkins.Client, parameter jenkins.Parameter, w fyne.Window) *parameterInput {
+	input := &parameterInput{parameter: parameter}
+	defaultValue := parameter.Default.Value
+
+	switch parameter.Kind() {
+	case jenkins.BooleanParameter:
+		check := widget.NewCheck("", nil)
+		check.SetChecked(defaultValue.Bool())
+		input.widget = check
+		input.value = func() string { return strconv.FormatBool(check.Checked) }
+		input.set = func(value string) { check.SetChecked(jenkins.Value(value).Bool()) }
+
+	case jenkins.ChoiceParameter:
+		choice := widget.NewSelect(parameter.Choices, nil)
+		if defaultValue != "" {
+			choice.SetSelected(defaultValue.String())
+		} else if len(parameter.Choices) > 0 {
+			choice.SetSelectedIndex(0)
+		}
+		input.widget = choice
+		input.value = func() string { return choice.Selected }
+		input.set = choice.SetSelected
+
+	case jenkins.TextParameter:
+		entry := widget.NewMultiLineEntry()
+		entry.SetMinRowsVisible(4)
+		entry.SetText(defaultValue.String())
+		input.widget = entry
+		input.value = func() string { return entry.Text }
+		input.set = entry.SetText
+
+	case jenkins.PasswordParameter:
+		entry := widget.NewPasswordEntry()
+		entry.SetText(defaultValue.String())
+		input.widget = entry
+		input.value = func() string { return entry.Text }
+		input.set = entry.SetText
+
+	case jenkins.RunParameter:
+		entry := widget.NewSelectEntry(nil)
+		entry.SetPlaceHolder(parameter.ProjectName + "#number")
+		entry.SetText(defaultValue.String())
+		input.widget = entry
+		input.value = func() string { return entry.Text }
+		input.set = entry.SetText
+
+		go func() {
+			job, err := client.GetJob(ctx, jenkins.JobPath(parameter.ProjectName))
+			if err != nil {
+				fmt.Println("Error fetching builds of " + parameter.ProjectName + ": " + err.Error())
+				return
+			}
+
+			options := make([]string, len(job.Builds))
+			for i, build := range job.Builds {
+				options[i] = fmt.Sprintf("%s#%d", parameter.ProjectName, build.Number)
+			}
+			fyne.Do(func() { entry.SetOptions(options) })
+		}()
+
+	case jenkins.FileParameter:
+		label := widget.NewLabel("No file selected")
+		button := widget.NewButtonWithIcon("", theme.FileIcon(), func() {
+			dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				if reader == nil {
+					return
+				}
+				defer reader.Close()
+
+				content, err := io.ReadAll(reader)
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+
+				name := reader.URI().Name()
+				input.file = &jenkins.File{Name: name, Content: bytes.NewReader(content)}
+				label.SetText(name)
+			}, w)
+		})
+		input.widget = container.NewBorder(nil, nil, nil, button, label)
+		input.value = func() string { return "" }
+		input.set = func(string) {}
+
+	default:
+		entry := widget.NewEntry()
+		entry.SetText(defaultValue.String())
+		input.widget = entry
+		input.value = func() string { return entry.Text }
+		input.set = entry.SetText
+	}
+
+	return input
+}
+
+// remembered tells if the value may be stored in the preferences, which
+// excludes secrets and uploaded files.
+func (p *parameterInput) remembered() bool {
+	kind := p.parameter.Kind()
+	return kind != jenkins.PasswordParameter && kind != jenkins.FileParameter
+}
+
+func (p *parameterInput) formItem() *widget.FormItem {
+	item := widget.NewFormItem(p.parameter.Name, p.widget)
+	item.HintText = p.parameter.Desc
+	return item
+}
+
+// launchRequest returns the request triggering the job with the values
+// currently entered in inputs.
+func launchRequest(ctx context.Context, client *jenkins.Client, jobURL string, inputs []*parameterInput) func() (string, error) {
+	data := url.Values{}
+	files := map[string]jenkins.File{}
+	for _, input := range inputs {
+		if input.parameter.Kind() == jenkins.FileParameter {
+			if input.file != nil {
+				files[input.parameter.Name] = *input.file
+			}
+			continue
+		}
+		data.Add(input.parameter.Name, input.value())
+	}
+
+	if len(files) > 0 {
+		return func() (string, error) {
+			return client.BuildWithFiles(ctx, jobURL, data, files)
+		}
+	}
+	return func() (string, error) {
+		return client.BuildWithParameters(ctx, jobURL, data)
+	}
+}
+
+func inputValues(inputs []*parameterInput) map[string]string {
+	values := map[string]string{}
+	for _, input := range inputs {
+		if input.remembered() {
+			values[input.parameter.Name] = input.value()
+		}
+	}
+	return values
+}
+
+func setInputValues(inputs []*parameterInput, values map[string]string) {
+	for _, input := range inputs {
+		if value, ok := values[input.parameter.Name]; ok && input.remembered() {
+			input.set(value)
+		}
+	}
+}
+
+// showParametersDialog asks for the parameters of a job, prefilled with the
+// values of its last launch, and lets the user pick or save named presets.
+func showParametersDialog(ctx context.Context, client *jenkins.Client, prefs fyne.Preferences, job jenkins.Job, parameters []jenkins.Parameter, w fyne.Window, launch func(request func() (string, error))) {
+	presets := loadPresets(prefs, job.URL)
+
+	inputs := make([]*parameterInput, len(parameters))
+	widgets := make([]*widget.FormItem, len(parameters))
+	for i, parameter := range parameters {
+		inputs[i] = newParameterInput(ctx, client, parameter, w)
+		widgets[i] = inputs[i].formItem()
+	}
+	setInputValues(inputs, presets.Last)
+
+	presetSelect := widget.NewSelect(presets.names(), func(name string) {
+		setInputValues(inputs, presets.Presets[name])
+	})
+	presetSelect.PlaceHolder = "Last used values"
+
+	saveButton := widget.NewButtonWithIcon("", theme.DocumentSaveIcon(), func() {
+		nameEntry := widget.NewEntry()
+		nameEntry.SetText(presetSelect.Selected)
+		dialog.ShowForm("Save preset", "Save", "Cancel",
+			[]*widget.FormItem{widget.NewFormItem("Name", nameEntry)},
+			func(accept bool) {
+				if !accept || nameEntry.Text == "" {
+					return
+				}
+				presets.Presets[nameEntry.Text] = inputValues(inputs)
+				presets.save(prefs, job.URL)
+				presetSelect.SetOptions(presets.names())
+				presetSelect.SetSelected(nameEntry.Text)
+			}, w,
+		)
+	})
+	deleteButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		if presetSelect.Selected == "" {
+			return
+		}
+		delete(presets.Presets, presetSelect.Selected)
+		presets.save(prefs, job.URL)
+		presetSelect.ClearSelected()
+		presetSelect.SetOptions(presets.names())
+	})
+
+	presetItem := widget.NewFormItem("Preset", container.NewBorder(nil, nil, nil,
+		container.NewHBox(saveButton, deleteButton), presetSelect,
+	))
+
+	dialog.ShowForm("Job properties", "Launch", "Cancel",
+		append([]*widget.FormItem{presetItem}, widgets...),
+		func(accept bool) {
+			if !accept {
+				return
+			}
+			presets.Last = inputValues(inputs)
+			presets.save(prefs, job.URL)
+			launch(launchRequest(ctx, client, job.URL, inputs))
+		}, w,
+	)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"fyne.io/fyne/v2"
+)
+
+// jobPresets are the parameter values remembered for a job: the ones of its
+// last launch and the presets saved by name.
+type jobPresets struct {
+	Last    map[string]string            `json:"last"`
+	Presets map[string]map[string]string `json:"presets"`
+}
+
+func presetsKey(jobURL string) string {
+	return "presets:" + jobURL
+}
+
+func loadPresets(prefs fyne.Preferences, jobURL string) *jobPresets {
+	presets := &jobPresets{}
+	if data := prefs.String(presetsKey(jobURL)); data != "" {
+		if err := json.Unmarshal([]byte(data), presets); err != nil {
+			fmt.Println("Error reading presets of " + jobURL + ": " + err.Error())
+		}
+	}
+	if presets.Presets == nil {
+		presets.Presets = map[string]map[string]string{}
+	}
+	return presets
+}
+
+func (p *jobPresets) save(prefs fyne.Preferences, jobURL string) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		fmt.Println("Error saving presets of " + jobURL + ": " + err.Error())
+		return
+	}
+	prefs.SetString(presetsKey(jobURL), string(data))
+}
+
+func (p *jobPresets) names() []string {
+	names := make([]string, 0, len(p.Presets))
+	for name := range p.Presets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,554 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"webservices/jenkins"
+	"webservices/secrets"
+)
+
+// allProfiles is the pseudo profile combining the jobs of every server.
+const allProfiles = "All servers"
+
+// profile is a Jenkins server the app can connect to.
+type profile struct {
+	Name     string
+	URL      string
+	Username string
+	Token    string
+	TLS      jenkins.TLS
+	Proxy    jenkins.Proxy
+}
+
+func profileKey(name, field string) string {
+	return "profile:" + name + ":" + field
+}
+
+// profileFields are the profile settings kept in the preferences, the token
+// and the passwords go to the secret store.
+var profileFields = []string{"url", "username", "ca", "fingerprint", "cert", "proxy", "proxyuser", "noproxy"}
+
+// profileSecrets are the profile settings kept in the secret store.
+var profileSecrets = []string{"token", "key", "proxypassword"}
+
+// secretStore keeps the tokens and keys of the profiles, it is set up in
+// main before any profile is loaded.
+var secretStore *secrets.Cache
+
+// secretsPersistent is false when no secret store could be opened, in which
+// case the secrets stay in the preferences rather than being lost on exit.
+var secretsPersistent = true
+
+func loadSecret(prefs fyne.Preferences, name, field string) string {
+	key := profileKey(name, field)
+	// The preferences keep the secrets not written to the store yet, see
+	// saveSecret and migrateSecrets.
+	if value := prefs.String(key); value != "" || !secretsPersistent {
+		return value
+	}
+
+	value, err := secretStore.Get(key)
+	if err != nil && !errors.Is(err, secrets.ErrNotFound) {
+		fmt.Println("Error reading " + field + " of " + name + ": " + err.Error())
+	}
+	return value
+}
+
+// secretUnreadable tells whether the secret could not be read from the
+// store, in which case it is kept rather than cleared when saving.
+func secretUnreadable(name, field string) bool {
+	if !secretsPersistent {
+		return false
+	}
+	_, err := secretStore.Get(profileKey(name, field))
+	return err != nil && !errors.Is(err, secrets.ErrNotFound)
+}
+
+func saveSecret(prefs fyne.Preferences, name, field, value string) {
+	key := profileKey(name, field)
+	if !secretsPersistent {
+		if value == "" {
+			prefs.RemoveValue(key)
+		} else {
+			prefs.SetString(key, value)
+		}
+		return
+	}
+
+	if value == "" {
+		if secretUnreadable(name, field) {
+			return
+		}
+		prefs.RemoveValue(key)
+		secretStore.Delete(key)
+		return
+	}
+	if saved, err := secretStore.Get(key); err == nil && saved == value && prefs.String(key) == "" {
+		return
+	}
+	// The store is written in the background, the preferences keep the
+	// secret until then, see secretSaved.
+	prefs.SetString(key, value)
+	secretStore.Set(key, value)
+}
+
+// secretSaved is called once the secret store was written in the background.
+// The copy of the secret kept in the preferences is only dropped once saved,
+// so that a failed write does not lose it.
+func secretSaved(prefs fyne.Preferences, key, value string, err error) {
+	if err != nil {
+		fmt.Println("Error saving secret " + key + ": " + err.Error())
+		return
+	}
+	if value != "" && prefs.String(key) == value {
+		prefs.RemoveValue(key)
+	}
+}
+
+// newClient returns a client for the profile and the error of its settings,
+// if any, in which case the client falls back to the defaults.
+func (p profile) newClient() (*jenkins.Client, error) {
+	client := jenkins.NewClient(p.URL, p.Username, p.Token)
+	if err := client.SetTLS(p.TLS); err != nil {
+		return client, fmt.Errorf("invalid TLS settings: %w", err)
+	}
+	if err := client.SetProxy(p.Proxy); err != nil {
+		return client, fmt.Errorf("invalid proxy settings: %w", err)
+	}
+	return client, nil
+}
+
+func (p profile) client() *jenkins.Client {
+	client, err := p.newClient()
+	if err != nil {
+		fmt.Println("Error setting up " + p.Name + ": " + err.Error())
+	}
+	return client
+}
+
+func profileNames(prefs fyne.Preferences) []string {
+	return prefs.StringList("profiles")
+}
+
+func loadProfile(prefs fyne.Preferences, name string) profile {
+	return profile{
+		Name:     name,
+		URL:      prefs.String(profileKey(name, "url")),
+		Username: prefs.String(profileKey(name, "username")),
+		Token:    loadSecret(prefs, name, "token"),
+		TLS: jenkins.TLS{
+			CA:          prefs.String(profileKey(name, "ca")),
+			Fingerprint: prefs.String(profileKey(name, "fingerprint")),
+			Cert:        prefs.String(profileKey(name, "cert")),
+			Key:         loadSecret(prefs, name, "key"),
+		},
+		Proxy: jenkins.Proxy{
+			URL:      prefs.String(profileKey(name, "proxy")),
+			Username: prefs.String(profileKey(name, "proxyuser")),
+			Password: loadSecret(prefs, name, "proxypassword"),
+			NoProxy:  prefs.String(profileKey(name, "noproxy")),
+		},
+	}
+}
+
+func loadProfiles(prefs fyne.Preferences) []profile {
+	var profiles []profile
+	for _, name := range profileNames(prefs) {
+		profiles = append(profiles, loadProfile(prefs, name))
+	}
+	return profiles
+}
+
+func saveProfile(prefs fyne.Preferences, p profile) {
+	prefs.SetString(profileKey(p.Name, "url"), p.URL)
+	prefs.SetString(profileKey(p.Name, "username"), p.Username)
+	prefs.SetString(profileKey(p.Name, "ca"), p.TLS.CA)
+	prefs.SetString(profileKey(p.Name, "fingerprint"), p.TLS.Fingerprint)
+	prefs.SetString(profileKey(p.Name, "cert"), p.TLS.Cert)
+	saveSecret(prefs, p.Name, "token", p.Token)
+	saveSecret(prefs, p.Name, "key", p.TLS.Key)
+	prefs.SetString(profileKey(p.Name, "proxy"), p.Proxy.URL)
+	prefs.SetString(profileKey(p.Name, "proxyuser"), p.Proxy.Username)
+	prefs.SetString(profileKey(p.Name, "noproxy"), p.Proxy.NoProxy)
+	saveSecret(prefs, p.Name, "proxypassword", p.Proxy.Password)
+
+	if names := profileNames(prefs); !slices.Contains(names, p.Name) {
+		prefs.SetStringList("profiles", append(names, p.Name))
+	}
+}
+
+func deleteProfile(prefs fyne.Preferences, name string) {
+	for _, field := range profileFields {
+		prefs.RemoveValue(profileKey(name, field))
+	}
+	for _, field := range profileSecrets {
+		saveSecret(prefs, name, field, "")
+	}
+	prefs.RemoveValue(favoritesKey(name))
+	jobCache.drop(name)
+
+	names := slices.DeleteFunc(profileNames(prefs), func(n string) bool { return n == name })
+	prefs.SetStringList("profiles", names)
+	if prefs.String("profile") == name {
+		prefs.RemoveValue("profile")
+	}
+}
+
+// currentProfile returns the name of the selected profile, which may be
+// allProfiles, or an empty string when no profile exists yet.
+func currentProfile(prefs fyne.Preferences) string {
+	names := profileNames(prefs)
+	current := prefs.String("profile")
+	if current == allProfiles || slices.Contains(names, current) {
+		return current
+	}
+	if len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+// migrateProfiles moves the single server configuration of older versions
+// into a profile named Default. Its token is left in clear text for
+// migrateSecrets to move.
+func migrateProfiles(prefs fyne.Preferences) {
+	url := prefs.String("url")
+	if url == "" || len(profileNames(prefs)) > 0 {
+		return
+	}
+
+	prefs.SetString(profileKey("Default", "url"), url)
+	prefs.SetString(profileKey("Default", "username"), prefs.String("username"))
+	prefs.SetString(profileKey("Default", "token"), prefs.String("password"))
+	prefs.SetStringList("profiles", []string{"Default"})
+	prefs.SetStringList(favoritesKey("Default"), prefs.StringList("favorites:"+url))
+
+	prefs.RemoveValue("url")
+	prefs.RemoveValue("username")
+	prefs.RemoveValue("password")
+	prefs.RemoveValue("favorites:" + url)
+}
+
+// migrateSecrets moves the secrets kept in clear text in the preferences, by
+// older versions or while no secret store could be opened, to store. They are
+// only removed from the preferences once saved.
+func migrateSecrets(prefs fyne.Preferences, store secrets.Store) {
+	for _, name := range profileNames(prefs) {
+		for _, field := range profileSecrets {
+			key := profileKey(name, field)
+			value := prefs.String(key)
+			if value == "" {
+				continue
+			}
+			if err := store.Set(key, value); err != nil {
+				fmt.Println("Error migrating " + field + " of " + name + ": " + err.Error())
+				continue
+			}
+			prefs.RemoveValue(key)
+		}
+	}
+}
+
+// loadSecrets migrates the secrets left in the preferences to store, then
+// reads the secrets of every profile into cache. It blocks while the store
+// waits for the user, so it must not run on the UI goroutine.
+func loadSecrets(prefs fyne.Preferences, store secrets.Store, cache *secrets.Cache) {
+	migrateSecrets(prefs, store)
+
+	var keys []string
+	for _, name := range profileNames(prefs) {
+		for _, field := range profileSecrets {
+			keys = append(keys, profileKey(name, field))
+		}
+	}
+	if err := cache.Load(keys); err != nil {
+		fmt.Println("Error reading secrets: " + err.Error())
+	}
+}
+
+// checkConnection tells which Jenkins version the profile connects to and as
+// which user, or what prevents it from using the server.
+func checkConnection(ctx context.Context, p profile) (string, error) {
+	client, err := p.newClient()
+	if err != nil {
+		return "", err
+	}
+
+	user, err := client.WhoAmI(ctx)
+	switch {
+	case errors.Is(err, jenkins.ErrUnauthorized):
+		return "", errors.New("the username or token is wrong")
+	case errors.Is(err, jenkins.ErrNotFound):
+		return "", errors.New("no Jenkins server found at " + p.URL)
+	case err != nil:
+		return "", err
+	}
+	if p.Token != "" && user.Anonymous {
+		return "", errors.New("the credentials were ignored, connected as anonymous")
+	}
+
+	server, err := client.GetServer(ctx)
+	switch {
+	case errors.Is(err, jenkins.ErrForbidden):
+		return "", errors.New(user.Name + " is missing the Overall/Read permission")
+	case err != nil:
+		return "", err
+	case server.Version == "":
+		return "", errors.New("no Jenkins server found at " + p.URL)
+	}
+	return fmt.Sprintf("Jenkins %s, connected as %s", server.Version, user.Name), nil
+}
+
+// showProfileDialog edits a profile, or creates one when p has no name yet.
+// onChanged is called with the name of the saved profile, or with an empty
+// name when the profile was deleted. The connection is tested before saving.
+func showProfileDialog(w fyne.Window, prefs fyne.Preferences, p profile, onChanged func(name string)) {
+	creating := p.Name == ""
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(p.Name)
+	nameEntry.SetPlaceHolder("dev, qa, prod...")
+
+	urlEntry := widget.NewEntry()
+	urlEntry.SetText(p.URL)
+	urlEntry.SetPlaceHolder("https://jenkins.example.com")
+
+	userEntry := widget.NewEntry()
+	userEntry.SetText(p.Username)
+
+	tokenEntry := widget.NewPasswordEntry()
+	tokenEntry.SetText(p.Token)
+	if !creating && secretUnreadable(p.Name, "token") {
+		tokenEntry.SetPlaceHolder("Could not be read, kept unless replaced")
+	}
+
+	tlsInputs := newTLSInputs(p.TLS, w)
+
+	proxyEntry := widget.NewEntry()
+	proxyEntry.SetText(p.Proxy.URL)
+	proxyEntry.SetPlaceHolder("http://proxy:3128, socks5://localhost:1080")
+
+	proxyUserEntry := widget.NewEntry()
+	proxyUserEntry.SetText(p.Proxy.Username)
+
+	proxyPasswordEntry := widget.NewPasswordEntry()
+	proxyPasswordEntry.SetText(p.Proxy.Password)
+	if !creating && secretUnreadable(p.Name, "proxypassword") {
+		proxyPasswordEntry.SetPlaceHolder("Could not be read, kept unless replaced")
+	}
+
+	noProxyEntry := widget.NewEntry()
+	noProxyEntry.SetText(p.Proxy.NoProxy)
+	noProxyEntry.SetPlaceHolder("localhost, .internal, 10.0.0.0/8")
+
+	// entered returns the profile as typed, with its URL normalized.
+	entered := func() (profile, error) {
+		url, err := jenkins.NormalizeURL(urlEntry.Text)
+		if err != nil {
+			return profile{}, err
+		}
+		urlEntry.SetText(url)
+		return profile{
+			Name:     strings.TrimSpace(nameEntry.Text),
+			URL:      url,
+			Username: strings.TrimSpace(userEntry.Text),
+			Token:    strings.TrimSpace(tokenEntry.Text),
+			TLS:      tlsInputs.settings,
+			Proxy: jenkins.Proxy{
+				URL:      strings.TrimSpace(proxyEntry.Text),
+				Username: strings.TrimSpace(proxyUserEntry.Text),
+				Password: proxyPasswordEntry.Text,
+				NoProxy:  strings.TrimSpace(noProxyEntry.Text),
+			},
+		}, nil
+	}
+
+	// test checks the connection in the background, done is called from the
+	// UI goroutine with the outcome. When the certificate of the server is not
+	// trusted the user is offered to pin it, p being then updated.
+	var test func(p profile, done func(p profile, status string, err error))
+	test = func(p profile, done func(p profile, status string, err error)) {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
+			defer cancel()
+
+			status, err := checkConnection(ctx, p)
+			var cert *x509.Certificate
+			if jenkins.IsUntrusted(err) {
+				cert, _ = p.client().PeerCertificate(ctx)
+			}
+
+			fyne.Do(func() {
+				if cert == nil {
+					done(p, status, err)
+					return
+				}
+				message := fmt.Sprintf("The server presented a certificate which is not trusted:\n\n%s\nIssued by %s\nSHA-256 %s\n\nTrust this certificate for this profile?",
+					describeCertificate(cert), cert.Issuer.String(), jenkins.Fingerprint(cert))
+				trust := dialog.NewConfirm("Untrusted certificate", message, func(trust bool) {
+					if !trust {
+						done(p, status, err)
+						return
+					}
+					tlsInputs.trust(cert)
+					p.TLS = tlsInputs.settings
+					test(p, done)
+				}, w)
+				trust.Resize(fyne.NewSize(400, trust.MinSize().Height))
+				trust.Show()
+			})
+		}()
+	}
+
+	result := widget.NewLabel("")
+	result.Wrapping = fyne.TextWrapWord
+	result.Hide()
+
+	var testButton *widget.Button
+	testButton = widget.NewButtonWithIcon("Test connection", theme.SearchIcon(), func() {
+		edited, err := entered()
+		result.Show()
+		if err != nil {
+			result.SetText("Invalid URL: " + err.Error())
+			return
+		}
+
+		result.SetText("Testing connection...")
+		testButton.Disable()
+		test(edited, func(_ profile, status string, err error) {
+			testButton.Enable()
+			if err != nil {
+				result.SetText("Connection failed: " + err.Error())
+			} else {
+				result.SetText(status)
+			}
+		})
+	})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Profile", nameEntry),
+		widget.NewFormItem("Jenkins URL", urlEntry),
+		widget.NewFormItem("Username", userEntry),
+		widget.NewFormItem("User Token", tokenEntry),
+	}
+	proxyItem := widget.NewFormItem("Proxy", proxyEntry)
+	proxyItem.HintText = "Leave empty to use the proxy of the system"
+	items = append(items,
+		proxyItem,
+		widget.NewFormItem("Proxy user", proxyUserEntry),
+		widget.NewFormItem("Proxy password", proxyPasswordEntry),
+		widget.NewFormItem("No proxy for", noProxyEntry),
+	)
+	items = append(items, tlsInputs.formItems()...)
+	items = append(items, widget.NewFormItem("", container.NewVBox(testButton, result)))
+
+	var form dialog.Dialog
+	if !creating {
+		deleteButton := widget.NewButtonWithIcon("Delete profile", theme.DeleteIcon(), func() {
+			dialog.ShowConfirm("Delete profile", "Delete the profile '"+p.Name+"'?", func(confirm bool) {
+				if confirm {
+					form.Hide()
+					deleteProfile(prefs, p.Name)
+					onChanged("")
+				}
+			}, w)
+		})
+		deleteButton.Importance = widget.DangerImportance
+		items = append(items, widget.NewFormItem("", deleteButton))
+	}
+
+	save := func(edited profile) {
+		if edited.URL != p.URL {
+			jobCache.drop(p.Name)
+		}
+		if !creating && edited.Name != p.Name {
+			prefs.SetStringList(favoritesKey(edited.Name), prefs.StringList(favoritesKey(p.Name)))
+			deleteProfile(prefs, p.Name)
+		}
+		saveProfile(prefs, edited)
+		onChanged(edited.Name)
+
+		fyne.CurrentApp().SendNotification(&fyne.Notification{
+			Title:   "Settings saved!",
+			Content: "Jenkins URL: " + edited.URL,
+		})
+	}
+
+	// The dialog stays open until the profile is valid, so that nothing typed
+	// is lost.
+	var saveButton *widget.Button
+	saveButton = widget.NewButtonWithIcon("Save", theme.ConfirmIcon(), func() {
+		edited, err := entered()
+		if err != nil {
+			return
+		}
+
+		saveButton.Disable()
+		result.Show()
+		result.SetText("Testing connection...")
+		test(edited, func(edited profile, _ string, err error) {
+			saveButton.Enable()
+			if err == nil {
+				form.Hide()
+				save(edited)
+				return
+			}
+			result.SetText("Connection failed: " + err.Error())
+			dialog.ShowConfirm("Connection failed", err.Error()+"\n\nSave the profile anyway?", func(confirm bool) {
+				if confirm {
+					form.Hide()
+					save(edited)
+				}
+			}, w)
+		})
+	})
+	saveButton.Importance = widget.HighImportance
+
+	// Save is only enabled while the name and the URL are valid.
+	validate := func(string) {
+		if nameEntry.Validate() == nil && urlEntry.Validate() == nil {
+			saveButton.Enable()
+		} else {
+			saveButton.Disable()
+		}
+	}
+	nameEntry.Validator = func(text string) error {
+		name := strings.TrimSpace(text)
+		switch {
+		case name == "" || name == allProfiles:
+			return errors.New("please choose another profile name")
+		case name != p.Name && slices.Contains(profileNames(prefs), name):
+			return errors.New("a profile named '" + name + "' already exists")
+		}
+		return nil
+	}
+	urlEntry.Validator = func(text string) error {
+		_, err := jenkins.NormalizeURL(text)
+		return err
+	}
+	nameEntry.OnChanged = validate
+	urlEntry.OnChanged = validate
+	validate("")
+
+	discardButton := widget.NewButtonWithIcon("Discard", theme.CancelIcon(), func() { form.Hide() })
+
+	// The settings do not fit small screens, so the form scrolls.
+	content := container.NewVScroll(widget.NewForm(items...))
+	custom := dialog.NewCustomWithoutButtons("Setup Jenkins API", content, w)
+	custom.SetButtons([]fyne.CanvasObject{discardButton, saveButton})
+	form = custom
+	form.Resize(fyne.NewSize(450, min(content.Content.MinSize().Height+120, w.Canvas().Size().Height)))
+	form.Show()
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+
+	"webservices/secrets"
+)
+
+func TestCheckConnection(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		handler http.HandlerFunc
+		want    string
+	}{
+		{"connected", "token", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Jenkins", "2.462.3")
+			fmt.Fprint(w, `{"name":"alice","authenticated":true}`)
+		}, "Jenkins 2.462.3, connected as alice"},
+		{"wrong token", "token", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		}, "the username or token is wrong"},
+		{"not jenkins", "", func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		}, "no Jenkins server found"},
+		{"no version header", "", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"name":"anonymous","anonymous":true}`)
+		}, "no Jenkins server found"},
+		{"anonymous", "token", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Jenkins", "2.462.3")
+			fmt.Fprint(w, `{"name":"anonymous","anonymous":true}`)
+		}, "connected as anonymous"},
+		{"no read permission", "token", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/whoAmI/api/json" {
+				fmt.Fprint(w, `{"name":"alice","authenticated":true}`)
+				return
+			}
+			http.Error(w, "forbidden", http.StatusForbidden)
+		}, "alice is missing the Overall/Read permission"},
+	}
+	for _, test := range tests {
+		server := httptest.NewServer(test.handler)
+		p := profile{Name: "test", URL: server.URL, Username: "alice", Token: test.token}
+
+		status, err := checkConnection(context.Background(), p)
+		got := status
+		if err != nil {
+			got = err.Error()
+		}
+		if !strings.Contains(got, test.want) {
+			t.Errorf("%s: checkConnection = %q, want %q", test.name, got, test.want)
+		}
+		server.Close()
+	}
+}
+
+// flakyStore fails like a keyring left locked or whose prompt was dismissed.
+type flakyStore struct {
+	*secrets.MemoryStore
+	failGet, failSet bool
+}
+
+var errDismissed = errors.New("prompt dismissed")
+
+func (s flakyStore) Get(key string) (string, error) {
+	if s.failGet {
+		return "", errDismissed
+	}
+	return s.MemoryStore.Get(key)
+}
+
+func (s flakyStore) Set(key, value string) error {
+	if s.failSet {
+		return errDismissed
+	}
+	return s.MemoryStore.Set(key, value)
+}
+
+func useSecretStore(t *testing.T, store secrets.Store) (fyne.Preferences, *secrets.Cache) {
+	prefs := test.NewTempApp(t).Preferences()
+	cache := secrets.NewCache(store)
+	cache.OnWritten = func(key, value string, err error) {
+		secretSaved(prefs, key, value, err)
+	}
+	previous := secretStore
+	secretStore = cache
+	t.Cleanup(func() { secretStore = previous })
+	return prefs, cache
+}
+
+func TestSaveSecret(t *testing.T) {
+	key := profileKey("dev", "token")
+	tests := []struct {
+		name      string
+		failSet   bool
+		wantPrefs string
+		wantStore string
+	}{
+		{"saved", false, "", "s3cret"},
+		{"write failed", true, "s3cret", ""},
+	}
+	for _, test := range tests {
+		store := flakyStore{MemoryStore: secrets.NewMemoryStore(), failSet: test.failSet}
+		prefs, cache := useSecretStore(t, store)
+
+		saveSecret(prefs, "dev", "token", "s3cret")
+		cache.Flush()
+		if got := prefs.String(key); got != test.wantPrefs {
+			t.Errorf("%s: the preferences have %q, want %q", test.name, got, test.wantPrefs)
+		}
+		if got, _ := store.MemoryStore.Get(key); got != test.wantStore {
+			t.Errorf("%s: the store has %q, want %q", test.name, got, test.wantStore)
+		}
+		if got := loadSecret(prefs, "dev", "token"); got != "s3cret" {
+			t.Errorf("%s: loadSecret = %q, want the saved secret", test.name, got)
+		}
+	}
+}
+
+func TestSaveUnreadableSecret(t *testing.T) {
+	key := profileKey("dev", "token")
+	store := flakyStore{MemoryStore: secrets.NewMemoryStore(), failGet: true}
+	store.MemoryStore.Set(key, "s3cret")
+	prefs, cache := useSecretStore(t, store)
+	cache.Load([]string{key})
+
+	if !secretUnreadable("dev", "token") {
+		t.Error("the secret is readable")
+	}
+	saveSecret(prefs, "dev", "token", "")
+	cache.Flush()
+	if got, err := store.MemoryStore.Get(key); got != "s3cret" {
+		t.Errorf("saving an unread secret left %q, %v in the store", got, err)
+	}
+
+	// A new secret replaces it.
+	saveSecret(prefs, "dev", "token", "new")
+	cache.Flush()
+	if got, _ := store.MemoryStore.Get(key); got != "new" || secretUnreadable("dev", "token") {
+		t.Errorf("the store has %q after replacing the secret", got)
+	}
+}
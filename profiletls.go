@@ -0,0 +1,211 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"webservices/jenkins"
+)
+
+// tlsInputs edits the TLS settings of a profile in the setup dialog.
+type tlsInputs struct {
+	settings jenkins.TLS
+	window   fyne.Window
+
+	ca     *widget.Label
+	client *widget.Label
+	pin    *widget.Label
+}
+
+func newTLSInputs(settings jenkins.TLS, w fyne.Window) *tlsInputs {
+	t := &tlsInputs{
+		settings: settings,
+		window:   w,
+		ca:       widget.NewLabel(""),
+		client:   widget.NewLabel(""),
+		pin:      widget.NewLabel(""),
+	}
+	for _, label := range []*widget.Label{t.ca, t.client, t.pin} {
+		label.Wrapping = fyne.TextWrapWord
+	}
+	t.refresh()
+	return t
+}
+
+func (t *tlsInputs) refresh() {
+	t.ca.SetText("System CAs")
+	if t.settings.CA != "" {
+		t.ca.SetText("System CAs and " + describePEM(t.settings.CA))
+	}
+
+	switch {
+	case t.settings.Cert != "" && t.settings.Key != "":
+		t.client.SetText(describePEM(t.settings.Cert))
+	case t.settings.Cert != "":
+		t.client.SetText(describePEM(t.settings.Cert) + ", missing key")
+	case t.settings.Key != "":
+		t.client.SetText("Key without certificate")
+	default:
+		t.client.SetText("None")
+	}
+
+	t.pin.SetText("None")
+	if t.settings.Fingerprint != "" {
+		t.pin.SetText("SHA-256 " + t.settings.Fingerprint)
+	}
+}
+
+// trust pins the certificate of the server.
+func (t *tlsInputs) trust(cert *x509.Certificate) {
+	t.settings.Fingerprint = jenkins.Fingerprint(cert)
+	t.refresh()
+}
+
+func (t *tlsInputs) formItems() []*widget.FormItem {
+	importCA := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		importPEM(t.window, func(data string) error {
+			if !x509.NewCertPool().AppendCertsFromPEM([]byte(data)) {
+				return errors.New("no certificate found in the file")
+			}
+			t.settings.CA = data
+			return nil
+		}, t.refresh)
+	})
+	clearCA := widget.NewButtonWithIcon("", theme.ContentClearIcon(), func() {
+		t.settings.CA = ""
+		t.refresh()
+	})
+
+	importCert := widget.NewButton("Cert", func() {
+		importPEM(t.window, func(data string) error {
+			if describePEM(data) == "" {
+				return errors.New("no certificate found in the file")
+			}
+			t.settings.Cert = data
+			return t.checkKeyPair()
+		}, t.refresh)
+	})
+	importKey := widget.NewButton("Key", func() {
+		importPEM(t.window, func(data string) error {
+			if block, _ := pem.Decode([]byte(data)); block == nil || !strings.Contains(block.Type, "PRIVATE KEY") {
+				return errors.New("no private key found in the file")
+			}
+			t.settings.Key = data
+			return t.checkKeyPair()
+		}, t.refresh)
+	})
+	clearClient := widget.NewButtonWithIcon("", theme.ContentClearIcon(), func() {
+		t.settings.Cert, t.settings.Key = "", ""
+		t.refresh()
+	})
+
+	clearPin := widget.NewButtonWithIcon("", theme.ContentClearIcon(), func() {
+		t.settings.Fingerprint = ""
+		t.refresh()
+	})
+
+	pinItem := widget.NewFormItem("Pinned certificate", container.NewBorder(nil, nil, nil, clearPin, t.pin))
+	pinItem.HintText = "Self signed certificates are pinned when testing the connection"
+	return []*widget.FormItem{
+		widget.NewFormItem("CA certificates", container.NewBorder(nil, nil, nil,
+			container.NewHBox(importCA, clearCA), t.ca,
+		)),
+		widget.NewFormItem("Client certificate", container.NewBorder(nil, nil, nil,
+			container.NewHBox(importCert, importKey, clearClient), t.client,
+		)),
+		pinItem,
+	}
+}
+
+// checkKeyPair makes sure that the client certificate and key belong
+// together once both are imported.
+func (t *tlsInputs) checkKeyPair() error {
+	if t.settings.Cert == "" || t.settings.Key == "" {
+		return nil
+	}
+	if _, err := tls.X509KeyPair([]byte(t.settings.Cert), []byte(t.settings.Key)); err != nil {
+		return fmt.Errorf("the certificate and key do not match: %w", err)
+	}
+	return nil
+}
+
+// importPEM lets the user pick a PEM file and passes its content to use,
+// showing the error it returns. done is called once the file is accepted.
+func importPEM(w fyne.Window, use func(data string) error, done func()) {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		data, err := io.ReadAll(reader)
+		if err == nil {
+			err = use(string(data))
+		}
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		done()
+	}, w)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".pem", ".crt", ".cer", ".key"}))
+	open.Show()
+}
+
+// describePEM names the first certificate of a PEM bundle, or returns an
+// empty string when there is none.
+func describePEM(data string) string {
+	rest := []byte(data)
+	count := 0
+	var first *x509.Certificate
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if first == nil {
+			first = cert
+		}
+		count++
+	}
+
+	switch count {
+	case 0:
+		return ""
+	case 1:
+		return describeCertificate(first)
+	}
+	return fmt.Sprintf("%s and %d more", describeCertificate(first), count-1)
+}
+
+func describeCertificate(cert *x509.Certificate) string {
+	name := cert.Subject.CommonName
+	if name == "" {
+		name = cert.Subject.String()
+	}
+	return fmt.Sprintf("%s (expires %s)", name, cert.NotAfter.Format("2006-01-02"))
+}
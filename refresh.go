@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"webservices/jenkins"
+)
+
+// jobsDiff counts how a refreshed job list differs from the shown one.
+type jobsDiff struct {
+	added, removed, changed int
+}
+
+// jobSignature holds everything about a job that is visible in the list.
+func jobSignature(job jenkins.Job) string {
+	return job.Title() + "|" + job.Color + "|" + jobDetail(job)
+}
+
+func diffJobs(old, new []jenkins.Job) jobsDiff {
+	signatures := make(map[string]string, len(old))
+	for _, job := range old {
+		signatures[job.URL] = jobSignature(job)
+	}
+
+	diff := jobsDiff{}
+	for _, job := range new {
+		signature, ok := signatures[job.URL]
+		switch {
+		case !ok:
+			diff.added++
+		case signature != jobSignature(job):
+			diff.changed++
+		}
+		delete(signatures, job.URL)
+	}
+	diff.removed = len(signatures)
+	return diff
+}
+
+func (d jobsDiff) empty() bool {
+	return d == jobsDiff{}
+}
+
+func (d jobsDiff) String() string {
+	if d.empty() {
+		return "Jobs are up to date"
+	}
+
+	var changes []string
+	if d.added > 0 {
+		changes = append(changes, fmt.Sprintf("%d new", d.added))
+	}
+	if d.removed > 0 {
+		changes = append(changes, fmt.Sprintf("%d removed", d.removed))
+	}
+	if d.changed > 0 {
+		changes = append(changes, fmt.Sprintf("%d updated", d.changed))
+	}
+	return "Jobs refreshed: " + strings.Join(changes, ", ")
+}
+
+const pullThreshold = 80
+
+// pullToRefresh is a handle shown above the list on mobile, pulling it down
+// refreshes the jobs.
+type pullToRefresh struct {
+	widget.BaseWidget
+	OnRefresh func()
+
+	label  *widget.Label
+	pulled float32
+}
+
+func newPullToRefresh(onRefresh func()) *pullToRefresh {
+	p := &pullToRefresh{OnRefresh: onRefresh, label: widget.NewLabel("")}
+	p.label.Importance = widget.LowImportance
+	p.label.SizeName = theme.SizeNameCaptionText
+	p.reset()
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+func (p *pullToRefresh) reset() {
+	p.pulled = 0
+	p.label.SetText("↓ Pull to refresh")
+}
+
+func (p *pullToRefresh) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewCenter(p.label))
+}
+
+func (p *pullToRefresh) Dragged(e *fyne.DragEvent) {
+	p.pulled += e.Dragged.DY
+	if p.pulled > pullThreshold {
+		p.label.SetText("↻ Release to refresh")
+	} else {
+		p.label.SetText("↓ Pull to refresh")
+	}
+}
+
+func (p *pullToRefresh) DragEnd() {
+	refresh := p.pulled > pullThreshold
+	p.reset()
+	if refresh && p.OnRefresh != nil {
+		p.OnRefresh()
+	}
+}
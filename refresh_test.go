@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"webservices/jenkins"
+)
+
+func TestDiffJobs(t *testing.T) {
+	job := func(name, color string, lastBuild int) jenkins.Job {
+		j := jenkins.Job{Name: name, URL: "https://ci.example.com/job/" + name + "/", Color: color}
+		if lastBuild > 0 {
+			j.LastBuild = &jenkins.Build{Number: lastBuild, Result: "SUCCESS"}
+		}
+		return j
+	}
+	old := []jenkins.Job{job("a", "blue", 1), job("b", "red", 4), job("c", "blue", 0)}
+
+	tests := []struct {
+		name   string
+		new    []jenkins.Job
+		want   jobsDiff
+		String string
+	}{
+		{"same", []jenkins.Job{job("a", "blue", 1), job("b", "red", 4), job("c", "blue", 0)}, jobsDiff{}, "Jobs are up to date"},
+		{"reordered", []jenkins.Job{job("c", "blue", 0), job("a", "blue", 1), job("b", "red", 4)}, jobsDiff{}, "Jobs are up to date"},
+		{"added", []jenkins.Job{job("a", "blue", 1), job("b", "red", 4), job("c", "blue", 0), job("d", "blue", 0)}, jobsDiff{added: 1}, "Jobs refreshed: 1 new"},
+		{"removed", []jenkins.Job{job("a", "blue", 1)}, jobsDiff{removed: 2}, "Jobs refreshed: 2 removed"},
+		{"color", []jenkins.Job{job("a", "blue_anime", 1), job("b", "red", 4), job("c", "blue", 0)}, jobsDiff{changed: 1}, "Jobs refreshed: 1 updated"},
+		{"new build", []jenkins.Job{job("a", "blue", 2), job("b", "red", 4), job("c", "blue", 0)}, jobsDiff{changed: 1}, "Jobs refreshed: 1 updated"},
+		{"all", []jenkins.Job{job("a", "red", 2), job("d", "blue", 0)}, jobsDiff{added: 1, removed: 2, changed: 1}, "Jobs refreshed: 1 new, 2 removed, 1 updated"},
+		{"empty", nil, jobsDiff{removed: 3}, "Jobs refreshed: 3 removed"},
+	}
+	for _, test := range tests {
+		diff := diffJobs(old, test.new)
+		if diff != test.want {
+			t.Errorf("%s: diffJobs = %+v, want %+v", test.name, diff, test.want)
+		}
+		if diff.String() != test.String {
+			t.Errorf("%s: String() = %q, want %q", test.name, diff.String(), test.String)
+		}
+	}
+}
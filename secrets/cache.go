@@ -0,0 +1,113 @@
+package secrets
+
+import (
+	"errors"
+	"sync"
+)
+
+// Cache keeps the secrets of a store in memory, so that they can be read
+// without waiting for the store, e.g. for the user to unlock the keyring.
+// The secrets are read beforehand by Load, and changes are written to the
+// store in the background, in order.
+type Cache struct {
+	store Store
+	// OnWritten is called from a background goroutine once a change was
+	// written to the store, or failed to be, value being empty for deletions.
+	OnWritten func(key, value string, err error)
+
+	mu      sync.Mutex
+	values  map[string]string
+	failed  map[string]error
+	writes  chan func()
+	pending sync.WaitGroup
+}
+
+func NewCache(store Store) *Cache {
+	c := &Cache{
+		store:  store,
+		values: map[string]string{},
+		failed: map[string]error{},
+		writes: make(chan func(), 64),
+	}
+	go func() {
+		for write := range c.writes {
+			write()
+			c.pending.Done()
+		}
+	}()
+	return c
+}
+
+// Load reads the secrets of keys from the store, which may block until the
+// user answers a prompt of the store. It must be called before the secrets
+// are changed.
+func (c *Cache) Load(keys []string) error {
+	var errs []error
+	for _, key := range keys {
+		value, err := c.store.Get(key)
+
+		c.mu.Lock()
+		switch {
+		case err == nil:
+			c.values[key] = value
+		case !errors.Is(err, ErrNotFound):
+			c.failed[key] = err
+			errs = append(errs, err)
+		}
+		c.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// Get returns the loaded secret, or the error which prevented loading it.
+func (c *Cache) Get(key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err, ok := c.failed[key]; ok {
+		return "", err
+	}
+	value, ok := c.values[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Set changes the secret right away, the store being updated later, see
+// OnWritten.
+func (c *Cache) Set(key, value string) error {
+	c.mu.Lock()
+	c.values[key] = value
+	delete(c.failed, key)
+	c.mu.Unlock()
+
+	c.write(key, value, func() error { return c.store.Set(key, value) })
+	return nil
+}
+
+// Delete removes the secret right away, the store being updated later.
+func (c *Cache) Delete(key string) error {
+	c.mu.Lock()
+	delete(c.values, key)
+	delete(c.failed, key)
+	c.mu.Unlock()
+
+	c.write(key, "", func() error { return c.store.Delete(key) })
+	return nil
+}
+
+func (c *Cache) write(key, value string, write func() error) {
+	c.pending.Add(1)
+	c.writes <- func() {
+		err := write()
+		if c.OnWritten != nil {
+			c.OnWritten(key, value, err)
+		}
+	}
+}
+
+// Flush waits for the changes to be written to the store.
+func (c *Cache) Flush() {
+	c.pending.Wait()
+}
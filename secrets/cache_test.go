@@ -0,0 +1,74 @@
+package secrets
+
+import (
+	"errors"
+	"testing"
+)
+
+// lockedStore fails to read the secrets, like a keyring left locked.
+type lockedStore struct {
+	*MemoryStore
+}
+
+var errLocked = errors.New("locked")
+
+func (lockedStore) Get(key string) (string, error) {
+	return "", errLocked
+}
+
+func TestCache(t *testing.T) {
+	store := NewMemoryStore()
+	store.Set("token", "s3cret")
+
+	cache := NewCache(store)
+	if err := cache.Load([]string{"token", "key"}); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := cache.Get("token"); err != nil || value != "s3cret" {
+		t.Errorf("Get = %q, %v, want the loaded secret", value, err)
+	}
+	if _, err := cache.Get("key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of a missing secret returned %v, want ErrNotFound", err)
+	}
+
+	cache.Set("key", "private")
+	cache.Delete("token")
+	if value, _ := cache.Get("key"); value != "private" {
+		t.Errorf("Get after Set = %q, want the new secret", value)
+	}
+	cache.Flush()
+	if value, err := store.Get("key"); err != nil || value != "private" {
+		t.Errorf("the store has %q, %v after Set", value, err)
+	}
+	if _, err := store.Get("token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("the store has the secret after Delete: %v", err)
+	}
+}
+
+func TestCacheLoadError(t *testing.T) {
+	store := lockedStore{NewMemoryStore()}
+	cache := NewCache(store)
+
+	var failed []string
+	cache.OnWritten = func(key, value string, err error) {
+		if err != nil {
+			failed = append(failed, key)
+		}
+	}
+
+	if err := cache.Load([]string{"token"}); !errors.Is(err, errLocked) {
+		t.Fatalf("Load returned %v, want the error of the store", err)
+	}
+	if _, err := cache.Get("token"); !errors.Is(err, errLocked) {
+		t.Errorf("Get returned %v, want the error of the store", err)
+	}
+
+	cache.Set("token", "s3cret")
+	if value, err := cache.Get("token"); err != nil || value != "s3cret" {
+		t.Errorf("Get after Set = %q, %v", value, err)
+	}
+	cache.Flush()
+	if len(failed) != 0 {
+		t.Errorf("writing %v failed", failed)
+	}
+}
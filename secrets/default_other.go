@@ -0,0 +1,9 @@
+//go:build !linux || (android && ci)
+
+package secrets
+
+// Default returns a FileStore in dir, the platform keystores not being
+// reachable from Go.
+func Default(app, dir string) (Store, error) {
+	return NewFileStore(dir)
+}
@@ -0,0 +1,191 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore keeps the secrets AES-GCM encrypted in a file of the app storage.
+// Unless the key is protected by a platform keystore, see Default, it is
+// stored next to the file, so it only protects against the secrets being
+// read from the preferences or from backups of the secrets file alone.
+type FileStore struct {
+	path string
+	aead cipher.AEAD
+	mu   sync.Mutex
+}
+
+// keyWrapper protects the key of a FileStore at rest.
+type keyWrapper interface {
+	wrap(key []byte) ([]byte, error)
+	unwrap(wrapped []byte) ([]byte, error)
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	return newFileStore(dir, nil)
+}
+
+// newFileStore opens the store in dir, its key being protected by wrapper
+// when not nil.
+func newFileStore(dir string, wrapper keyWrapper) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	var key []byte
+	var err error
+	if wrapper == nil {
+		key, err = loadKey(dir)
+	} else {
+		key, err = loadWrappedKey(dir, wrapper)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileStore{path: filepath.Join(dir, "secrets.json"), aead: aead}, nil
+}
+
+func newKey() ([]byte, error) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	return key, err
+}
+
+// loadKey reads the key kept in clear text in dir, creating it the first
+// time.
+func loadKey(dir string) ([]byte, error) {
+	keyPath := filepath.Join(dir, "secrets.key")
+	key, err := os.ReadFile(keyPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		if key, err = newKey(); err == nil {
+			err = os.WriteFile(keyPath, key, 0o600)
+		}
+	}
+	return key, err
+}
+
+// loadWrappedKey reads the key kept wrapped in dir, creating it the first
+// time. A key left in clear text by loadKey is wrapped, then removed.
+func loadWrappedKey(dir string, wrapper keyWrapper) ([]byte, error) {
+	wrappedPath := filepath.Join(dir, "secrets.key.wrapped")
+	wrapped, err := os.ReadFile(wrappedPath)
+	if err == nil {
+		return wrapper.unwrap(wrapped)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	keyPath := filepath.Join(dir, "secrets.key")
+	key, err := os.ReadFile(keyPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		key, err = newKey()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if wrapped, err = wrapper.wrap(key); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(wrappedPath, wrapped, 0o600); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(keyPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *FileStore) load() (map[string][]byte, error) {
+	secrets := map[string][]byte{}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return secrets, json.Unmarshal(data, &secrets)
+}
+
+func (s *FileStore) save(secrets map[string][]byte) error {
+	data, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o600)
+}
+
+func (s *FileStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	sealed, ok := secrets[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	size := s.aead.NonceSize()
+	if len(sealed) < size {
+		return "", errors.New("secrets: corrupted secret " + key)
+	}
+	value, err := s.aead.Open(nil, sealed[:size], sealed[size:], []byte(key))
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+func (s *FileStore) Set(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	secrets[key] = s.aead.Seal(nonce, nonce, []byte(value), []byte(key))
+	return s.save(secrets)
+}
+
+func (s *FileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[key]; !ok {
+		return nil
+	}
+	delete(secrets, key)
+	return s.save(secrets)
+}
@@ -0,0 +1,103 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// xorWrapper stands for a platform keystore in tests.
+type xorWrapper struct {
+	wrapped int
+}
+
+func (w *xorWrapper) wrap(key []byte) ([]byte, error) {
+	w.wrapped++
+	return w.xor(key), nil
+}
+
+func (w *xorWrapper) unwrap(wrapped []byte) ([]byte, error) {
+	return w.xor(wrapped), nil
+}
+
+func (w *xorWrapper) xor(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[i] = b ^ 0x5a
+	}
+	return out
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("token"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get of a missing secret returned %v, want ErrNotFound", err)
+	}
+	if err := store.Set("token", "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "secrets.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("s3cret")) {
+		t.Error("the secret is stored in clear text")
+	}
+
+	reopened, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := reopened.Get("token"); err != nil || value != "s3cret" {
+		t.Errorf("Get after reopening = %q, %v", value, err)
+	}
+
+	if err := reopened.Delete("token"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reopened.Get("token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete returned %v, want ErrNotFound", err)
+	}
+}
+
+func TestFileStoreWrappedKey(t *testing.T) {
+	dir := t.TempDir()
+	plain, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plain.Set("token", "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Opening the store with a wrapper moves the clear text key.
+	wrapper := &xorWrapper{}
+	store, err := newFileStore(dir, wrapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := store.Get("token"); err != nil || value != "s3cret" {
+		t.Errorf("Get after wrapping the key = %q, %v", value, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "secrets.key")); !os.IsNotExist(err) {
+		t.Errorf("the clear text key was not removed: %v", err)
+	}
+
+	store, err = newFileStore(dir, wrapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := store.Get("token"); err != nil || value != "s3cret" {
+		t.Errorf("Get after reopening = %q, %v", value, err)
+	}
+	if wrapper.wrapped != 1 {
+		t.Errorf("the key was wrapped %d times, want 1", wrapper.wrapped)
+	}
+}
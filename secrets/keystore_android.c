@@ -0,0 +1,289 @@
+//go:build android && !ci
+
+#include <jni.h>
+#include <stdlib.h>
+#include <string.h>
+
+#include "keystore_android.h"
+
+#define ENCRYPT_MODE 1
+#define DECRYPT_MODE 2
+#define PURPOSE_ENCRYPT 1
+#define PURPOSE_DECRYPT 2
+#define TAG_BITS 128
+
+#define FAILED(env) ((*env)->ExceptionCheck(env))
+
+// exception clears the pending Java exception and returns its description,
+// malloc'ed, or NULL when there is none.
+static char *exception(JNIEnv *env) {
+	if (!FAILED(env)) {
+		return NULL;
+	}
+	jthrowable thrown = (*env)->ExceptionOccurred(env);
+	(*env)->ExceptionClear(env);
+
+	char *message = NULL;
+	jclass throwable = (*env)->FindClass(env, "java/lang/Throwable");
+	jmethodID toString = throwable ? (*env)->GetMethodID(env, throwable, "toString", "()Ljava/lang/String;") : NULL;
+	jstring text = toString ? (*env)->CallObjectMethod(env, thrown, toString) : NULL;
+	if (text != NULL && !FAILED(env)) {
+		const char *chars = (*env)->GetStringUTFChars(env, text, NULL);
+		if (chars != NULL) {
+			message = strdup(chars);
+			(*env)->ReleaseStringUTFChars(env, text, chars);
+		}
+	}
+	(*env)->ExceptionClear(env);
+	return message ? message : strdup("java exception");
+}
+
+static jobjectArray string_array(JNIEnv *env, const char *value) {
+	jclass string = (*env)->FindClass(env, "java/lang/String");
+	if (string == NULL) {
+		return NULL;
+	}
+	return (*env)->NewObjectArray(env, 1, string, (*env)->NewStringUTF(env, value));
+}
+
+static jbyteArray byte_array(JNIEnv *env, const void *data, int len) {
+	jbyteArray array = (*env)->NewByteArray(env, len);
+	if (array != NULL) {
+		(*env)->SetByteArrayRegion(env, array, 0, len, (const jbyte *)data);
+	}
+	return array;
+}
+
+static void copy_out(JNIEnv *env, jbyteArray array, buffer *out) {
+	out->len = (*env)->GetArrayLength(env, array);
+	out->data = malloc(out->len > 0 ? out->len : 1);
+	(*env)->GetByteArrayRegion(env, array, 0, out->len, (jbyte *)out->data);
+}
+
+// get_key returns the key of the Android Keystore named alias, or NULL
+// without exception when there is none.
+static jobject get_key(JNIEnv *env, const char *alias) {
+	jclass keyStoreClass = (*env)->FindClass(env, "java/security/KeyStore");
+	if (keyStoreClass == NULL) {
+		return NULL;
+	}
+	jmethodID getInstance = (*env)->GetStaticMethodID(env, keyStoreClass, "getInstance", "(Ljava/lang/String;)Ljava/security/KeyStore;");
+	if (getInstance == NULL) {
+		return NULL;
+	}
+	jobject keyStore = (*env)->CallStaticObjectMethod(env, keyStoreClass, getInstance, (*env)->NewStringUTF(env, "AndroidKeyStore"));
+	if (FAILED(env)) {
+		return NULL;
+	}
+
+	jmethodID load = (*env)->GetMethodID(env, keyStoreClass, "load", "(Ljava/security/KeyStore$LoadStoreParameter;)V");
+	if (load == NULL) {
+		return NULL;
+	}
+	(*env)->CallVoidMethod(env, keyStore, load, NULL);
+	if (FAILED(env)) {
+		return NULL;
+	}
+
+	jmethodID getKey = (*env)->GetMethodID(env, keyStoreClass, "getKey", "(Ljava/lang/String;[C)Ljava/security/Key;");
+	if (getKey == NULL) {
+		return NULL;
+	}
+	return (*env)->CallObjectMethod(env, keyStore, getKey, (*env)->NewStringUTF(env, alias), NULL);
+}
+
+// generate_key creates an AES-GCM key named alias in the Android Keystore.
+static jobject generate_key(JNIEnv *env, const char *alias) {
+	const char *setter = "([Ljava/lang/String;)Landroid/security/keystore/KeyGenParameterSpec$Builder;";
+
+	jclass builderClass = (*env)->FindClass(env, "android/security/keystore/KeyGenParameterSpec$Builder");
+	if (builderClass == NULL) {
+		return NULL;
+	}
+	jmethodID constructor = (*env)->GetMethodID(env, builderClass, "<init>", "(Ljava/lang/String;I)V");
+	jmethodID setBlockModes = constructor ? (*env)->GetMethodID(env, builderClass, "setBlockModes", setter) : NULL;
+	jmethodID setPaddings = setBlockModes ? (*env)->GetMethodID(env, builderClass, "setEncryptionPaddings", setter) : NULL;
+	jmethodID setKeySize = setPaddings ? (*env)->GetMethodID(env, builderClass, "setKeySize", "(I)Landroid/security/keystore/KeyGenParameterSpec$Builder;") : NULL;
+	jmethodID build = setKeySize ? (*env)->GetMethodID(env, builderClass, "build", "()Landroid/security/keystore/KeyGenParameterSpec;") : NULL;
+	if (build == NULL) {
+		return NULL;
+	}
+
+	jobject builder = (*env)->NewObject(env, builderClass, constructor, (*env)->NewStringUTF(env, alias), PURPOSE_ENCRYPT | PURPOSE_DECRYPT);
+	if (FAILED(env)) {
+		return NULL;
+	}
+	jobjectArray modes = string_array(env, "GCM");
+	if (modes == NULL) {
+		return NULL;
+	}
+	(*env)->CallObjectMethod(env, builder, setBlockModes, modes);
+	if (FAILED(env)) {
+		return NULL;
+	}
+	jobjectArray paddings = string_array(env, "NoPadding");
+	if (paddings == NULL) {
+		return NULL;
+	}
+	(*env)->CallObjectMethod(env, builder, setPaddings, paddings);
+	if (FAILED(env)) {
+		return NULL;
+	}
+	(*env)->CallObjectMethod(env, builder, setKeySize, 256);
+	if (FAILED(env)) {
+		return NULL;
+	}
+	jobject spec = (*env)->CallObjectMethod(env, builder, build);
+	if (FAILED(env)) {
+		return NULL;
+	}
+
+	jclass generatorClass = (*env)->FindClass(env, "javax/crypto/KeyGenerator");
+	if (generatorClass == NULL) {
+		return NULL;
+	}
+	jmethodID getInstance = (*env)->GetStaticMethodID(env, generatorClass, "getInstance", "(Ljava/lang/String;Ljava/lang/String;)Ljavax/crypto/KeyGenerator;");
+	jmethodID init = getInstance ? (*env)->GetMethodID(env, generatorClass, "init", "(Ljava/security/spec/AlgorithmParameterSpec;)V") : NULL;
+	jmethodID generateKey = init ? (*env)->GetMethodID(env, generatorClass, "generateKey", "()Ljavax/crypto/SecretKey;") : NULL;
+	if (generateKey == NULL) {
+		return NULL;
+	}
+
+	jobject generator = (*env)->CallStaticObjectMethod(env, generatorClass, getInstance,
+		(*env)->NewStringUTF(env, "AES"), (*env)->NewStringUTF(env, "AndroidKeyStore"));
+	if (FAILED(env)) {
+		return NULL;
+	}
+	(*env)->CallVoidMethod(env, generator, init, spec);
+	if (FAILED(env)) {
+		return NULL;
+	}
+	return (*env)->CallObjectMethod(env, generator, generateKey);
+}
+
+// new_cipher returns an AES-GCM cipher initialized with key, params being
+// the GCM parameters or NULL for the Keystore to pick a random IV.
+static jobject new_cipher(JNIEnv *env, int mode, jobject key, jobject params) {
+	jclass cipherClass = (*env)->FindClass(env, "javax/crypto/Cipher");
+	if (cipherClass == NULL) {
+		return NULL;
+	}
+	jmethodID getInstance = (*env)->GetStaticMethodID(env, cipherClass, "getInstance", "(Ljava/lang/String;)Ljavax/crypto/Cipher;");
+	if (getInstance == NULL) {
+		return NULL;
+	}
+	jobject cipher = (*env)->CallStaticObjectMethod(env, cipherClass, getInstance, (*env)->NewStringUTF(env, "AES/GCM/NoPadding"));
+	if (FAILED(env)) {
+		return NULL;
+	}
+
+	if (params == NULL) {
+		jmethodID init = (*env)->GetMethodID(env, cipherClass, "init", "(ILjava/security/Key;)V");
+		if (init == NULL) {
+			return NULL;
+		}
+		(*env)->CallVoidMethod(env, cipher, init, mode, key);
+	} else {
+		jmethodID init = (*env)->GetMethodID(env, cipherClass, "init", "(ILjava/security/Key;Ljava/security/spec/AlgorithmParameterSpec;)V");
+		if (init == NULL) {
+			return NULL;
+		}
+		(*env)->CallVoidMethod(env, cipher, init, mode, key, params);
+	}
+	return FAILED(env) ? NULL : cipher;
+}
+
+static jbyteArray do_final(JNIEnv *env, jobject cipher, const void *data, int len) {
+	jclass cipherClass = (*env)->GetObjectClass(env, cipher);
+	jmethodID doFinal = (*env)->GetMethodID(env, cipherClass, "doFinal", "([B)[B");
+	if (doFinal == NULL) {
+		return NULL;
+	}
+	jbyteArray input = byte_array(env, data, len);
+	if (input == NULL) {
+		return NULL;
+	}
+	jbyteArray output = (*env)->CallObjectMethod(env, cipher, doFinal, input);
+	return FAILED(env) ? NULL : output;
+}
+
+static jbyteArray get_iv(JNIEnv *env, jobject cipher) {
+	jclass cipherClass = (*env)->GetObjectClass(env, cipher);
+	jmethodID getIV = (*env)->GetMethodID(env, cipherClass, "getIV", "()[B");
+	if (getIV == NULL) {
+		return NULL;
+	}
+	jbyteArray iv = (*env)->CallObjectMethod(env, cipher, getIV);
+	return FAILED(env) ? NULL : iv;
+}
+
+static jobject gcm_spec(JNIEnv *env, const void *iv, int iv_len) {
+	jclass specClass = (*env)->FindClass(env, "javax/crypto/spec/GCMParameterSpec");
+	if (specClass == NULL) {
+		return NULL;
+	}
+	jmethodID constructor = (*env)->GetMethodID(env, specClass, "<init>", "(I[B)V");
+	if (constructor == NULL) {
+		return NULL;
+	}
+	jbyteArray nonce = byte_array(env, iv, iv_len);
+	if (nonce == NULL) {
+		return NULL;
+	}
+	jobject spec = (*env)->NewObject(env, specClass, constructor, TAG_BITS, nonce);
+	return FAILED(env) ? NULL : spec;
+}
+
+// keystore_encrypt encrypts data with the key named alias, which is created
+// the first time. It returns NULL on success, the IV and the ciphertext being
+// stored in iv and out, or a malloc'ed error message.
+char *keystore_encrypt(uintptr_t jni_env, const char *alias, const void *data, int len, buffer *iv, buffer *out) {
+	JNIEnv *env = (JNIEnv *)jni_env;
+	if ((*env)->PushLocalFrame(env, 64) < 0) {
+		return exception(env);
+	}
+
+	jobject key = get_key(env, alias);
+	if (key == NULL && !FAILED(env)) {
+		key = generate_key(env, alias);
+	}
+	jobject cipher = key ? new_cipher(env, ENCRYPT_MODE, key, NULL) : NULL;
+	jbyteArray sealed = cipher ? do_final(env, cipher, data, len) : NULL;
+	jbyteArray nonce = sealed ? get_iv(env, cipher) : NULL;
+	if (nonce != NULL) {
+		copy_out(env, nonce, iv);
+		copy_out(env, sealed, out);
+	}
+
+	char *error = exception(env);
+	if (error == NULL && nonce == NULL) {
+		error = strdup("encryption failed");
+	}
+	(*env)->PopLocalFrame(env, NULL);
+	return error;
+}
+
+// keystore_decrypt decrypts data with the key named alias. It returns NULL
+// on success, the plaintext being stored in out, or a malloc'ed error
+// message.
+char *keystore_decrypt(uintptr_t jni_env, const char *alias, const void *iv, int iv_len, const void *data, int len, buffer *out) {
+	JNIEnv *env = (JNIEnv *)jni_env;
+	if ((*env)->PushLocalFrame(env, 64) < 0) {
+		return exception(env);
+	}
+
+	jobject key = get_key(env, alias);
+	jobject params = key ? gcm_spec(env, iv, iv_len) : NULL;
+	jobject cipher = params ? new_cipher(env, DECRYPT_MODE, key, params) : NULL;
+	jbyteArray plain = cipher ? do_final(env, cipher, data, len) : NULL;
+	if (plain != NULL) {
+		copy_out(env, plain, out);
+	}
+
+	char *error = exception(env);
+	if (error == NULL && plain == NULL) {
+		error = strdup(key == NULL ? "key not found" : "decryption failed");
+	}
+	(*env)->PopLocalFrame(env, NULL);
+	return error;
+}
@@ -0,0 +1,86 @@
+//go:build android && !ci
+
+package secrets
+
+/*
+#include <stdlib.h>
+
+#include "keystore_android.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+
+	"fyne.io/fyne/v2/driver"
+)
+
+// keystore wraps the key of a FileStore with an AES key of the Android
+// Keystore, which cannot be extracted from the device.
+type keystore struct {
+	alias string
+}
+
+// run calls fn with the JNI environment of the app, fn returning a malloc'ed
+// error message or nil.
+func (k keystore) run(fn func(env C.uintptr_t, alias *C.char) *C.char) error {
+	alias := C.CString(k.alias)
+	defer C.free(unsafe.Pointer(alias))
+
+	return driver.RunNative(func(ctx any) error {
+		android, ok := ctx.(*driver.AndroidContext)
+		if !ok {
+			return errors.New("secrets: no Android context")
+		}
+		if message := fn(C.uintptr_t(android.Env), alias); message != nil {
+			defer C.free(unsafe.Pointer(message))
+			return errors.New("secrets: keystore: " + C.GoString(message))
+		}
+		return nil
+	})
+}
+
+func goBytes(b C.buffer) []byte {
+	defer C.free(unsafe.Pointer(b.data))
+	return C.GoBytes(unsafe.Pointer(b.data), b.len)
+}
+
+// wrap encrypts key, the result starting with the length of the IV and the
+// IV picked by the Keystore.
+func (k keystore) wrap(key []byte) ([]byte, error) {
+	var iv, sealed C.buffer
+	err := k.run(func(env C.uintptr_t, alias *C.char) *C.char {
+		return C.keystore_encrypt(env, alias, unsafe.Pointer(&key[0]), C.int(len(key)), &iv, &sealed)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := goBytes(iv)
+	wrapped := append([]byte{byte(len(nonce))}, nonce...)
+	return append(wrapped, goBytes(sealed)...), nil
+}
+
+func (k keystore) unwrap(wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 2 || wrapped[0] == 0 || len(wrapped) <= 1+int(wrapped[0]) {
+		return nil, errors.New("secrets: corrupted key")
+	}
+	nonce, sealed := wrapped[1:1+wrapped[0]], wrapped[1+wrapped[0]:]
+
+	var plain C.buffer
+	err := k.run(func(env C.uintptr_t, alias *C.char) *C.char {
+		return C.keystore_decrypt(env, alias, unsafe.Pointer(&nonce[0]), C.int(len(nonce)),
+			unsafe.Pointer(&sealed[0]), C.int(len(sealed)), &plain)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return goBytes(plain), nil
+}
+
+// Default returns a FileStore in dir whose key is wrapped by a key of the
+// Android Keystore.
+func Default(app, dir string) (Store, error) {
+	return newFileStore(dir, keystore{alias: app + ".secrets"})
+}
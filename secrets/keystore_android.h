@@ -0,0 +1,10 @@
+#include <stdint.h>
+
+// buffer is a malloc'ed array of bytes returned to Go.
+typedef struct {
+	unsigned char *data;
+	int len;
+} buffer;
+
+char *keystore_encrypt(uintptr_t jni_env, const char *alias, const void *data, int len, buffer *iv, buffer *out);
+char *keystore_decrypt(uintptr_t jni_env, const char *alias, const void *iv, int iv_len, const void *data, int len, buffer *out);
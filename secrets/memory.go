@@ -0,0 +1,40 @@
+package secrets
+
+import "sync"
+
+// MemoryStore keeps the secrets for the lifetime of the process only.
+type MemoryStore struct {
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{secrets: map[string]string{}}
+}
+
+func (s *MemoryStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.secrets[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+func (s *MemoryStore) Set(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.secrets[key] = value
+	return nil
+}
+
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.secrets, key)
+	return nil
+}
@@ -0,0 +1,16 @@
+// Package secrets stores credentials outside of the plain text preferences,
+// in the keyring of the OS when there is one.
+package secrets
+
+import "errors"
+
+var ErrNotFound = errors.New("secrets: not found")
+
+// Store keeps secret values by key.
+type Store interface {
+	// Get returns ErrNotFound when there is no secret for the key.
+	Get(key string) (string, error)
+	Set(key, value string) error
+	// Delete does nothing when there is no secret for the key.
+	Delete(key string) error
+}
@@ -0,0 +1,195 @@
+//go:build linux && !android
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	secretsService   = "org.freedesktop.secrets"
+	secretsPath      = dbus.ObjectPath("/org/freedesktop/secrets")
+	defaultAlias     = dbus.ObjectPath("/org/freedesktop/secrets/aliases/default")
+	serviceInterface = "org.freedesktop.Secret.Service"
+	itemInterface    = "org.freedesktop.Secret.Item"
+	promptInterface  = "org.freedesktop.Secret.Prompt"
+
+	// callTimeout bounds the calls to the Secret Service, and promptTimeout
+	// how long the user has to answer a prompt of the keyring.
+	callTimeout   = 10 * time.Second
+	promptTimeout = 2 * time.Minute
+)
+
+// secret is the Secret structure of the Secret Service API.
+type secret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// SecretService keeps the secrets in the default collection of the Secret
+// Service, i.e. GNOME Keyring or KWallet, tagged with the application name.
+type SecretService struct {
+	app     string
+	conn    *dbus.Conn
+	session dbus.ObjectPath
+}
+
+func NewSecretService(app string) (*SecretService, error) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SecretService{app: app, conn: conn}
+	var output dbus.Variant
+	err = s.call(secretsPath, serviceInterface+".OpenSession", "plain", dbus.MakeVariant("")).Store(&output, &s.session)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// call invokes a method of the object at path, giving up after callTimeout.
+func (s *SecretService) call(path dbus.ObjectPath, method string, args ...any) *dbus.Call {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+	return s.conn.Object(secretsService, path).CallWithContext(ctx, method, 0, args...)
+}
+
+func (s *SecretService) attributes(key string) map[string]string {
+	return map[string]string{"application": s.app, "key": key}
+}
+
+func (s *SecretService) find(key string) (dbus.ObjectPath, error) {
+	var unlocked, locked []dbus.ObjectPath
+	err := s.call(secretsPath, serviceInterface+".SearchItems", s.attributes(key)).Store(&unlocked, &locked)
+	if err != nil {
+		return "", err
+	}
+	if len(unlocked) > 0 {
+		return unlocked[0], nil
+	}
+	if len(locked) == 0 {
+		return "", ErrNotFound
+	}
+
+	if err := s.unlock(locked[:1]); err != nil {
+		return "", err
+	}
+	return locked[0], nil
+}
+
+func (s *SecretService) unlock(objects []dbus.ObjectPath) error {
+	var unlocked []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+	if err := s.call(secretsPath, serviceInterface+".Unlock", objects).Store(&unlocked, &prompt); err != nil {
+		return err
+	}
+	return s.prompt(prompt)
+}
+
+// prompt shows the prompt of the keyring, e.g. asking for the password
+// unlocking the collection, and waits for the user to complete it. The prompt
+// is dismissed when the user does not answer within promptTimeout.
+func (s *SecretService) prompt(prompt dbus.ObjectPath) error {
+	if prompt == "/" {
+		return nil
+	}
+
+	options := []dbus.MatchOption{dbus.WithMatchObjectPath(prompt), dbus.WithMatchInterface(promptInterface)}
+	if err := s.conn.AddMatchSignal(options...); err != nil {
+		return err
+	}
+	defer s.conn.RemoveMatchSignal(options...)
+
+	signals := make(chan *dbus.Signal, 1)
+	s.conn.Signal(signals)
+	defer s.conn.RemoveSignal(signals)
+
+	if err := s.call(prompt, promptInterface+".Prompt", "").Err; err != nil {
+		return err
+	}
+
+	timeout := time.NewTimer(promptTimeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case signal, ok := <-signals:
+			if !ok {
+				return errors.New("secrets: keyring prompt interrupted")
+			}
+			if signal.Path != prompt || signal.Name != promptInterface+".Completed" {
+				continue
+			}
+			if len(signal.Body) > 0 {
+				if dismissed, _ := signal.Body[0].(bool); dismissed {
+					return errors.New("secrets: keyring prompt dismissed")
+				}
+			}
+			return nil
+		case <-timeout.C:
+			s.call(prompt, promptInterface+".Dismiss")
+			return errors.New("secrets: keyring prompt timed out")
+		}
+	}
+}
+
+func (s *SecretService) Get(key string) (string, error) {
+	item, err := s.find(key)
+	if err != nil {
+		return "", err
+	}
+
+	var value secret
+	if err := s.call(item, itemInterface+".GetSecret", s.session).Store(&value); err != nil {
+		return "", err
+	}
+	return string(value.Value), nil
+}
+
+func (s *SecretService) Set(key, value string) error {
+	properties := map[string]dbus.Variant{
+		itemInterface + ".Label":      dbus.MakeVariant(fmt.Sprintf("%s: %s", s.app, key)),
+		itemInterface + ".Attributes": dbus.MakeVariant(s.attributes(key)),
+	}
+	data := secret{Session: s.session, Value: []byte(value), ContentType: "text/plain"}
+
+	var item, prompt dbus.ObjectPath
+	call := s.call(defaultAlias, "org.freedesktop.Secret.Collection.CreateItem", properties, data, true)
+	if err := call.Store(&item, &prompt); err != nil {
+		return err
+	}
+	return s.prompt(prompt)
+}
+
+func (s *SecretService) Delete(key string) error {
+	item, err := s.find(key)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var prompt dbus.ObjectPath
+	if err := s.call(item, itemInterface+".Delete").Store(&prompt); err != nil {
+		return err
+	}
+	return s.prompt(prompt)
+}
+
+// Default returns the Secret Service when a keyring is running, falling
+// back to a FileStore in dir otherwise.
+func Default(app, dir string) (Store, error) {
+	if service, err := NewSecretService(app); err == nil {
+		return service, nil
+	}
+	return NewFileStore(dir)
+}
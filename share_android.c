@@ -0,0 +1,140 @@
+//go:build android && !ci
+
+#include <jni.h>
+#include <stdlib.h>
+#include <string.h>
+
+#include "share_android.h"
+
+#define FLAG_GRANT_READ_URI_PERMISSION 0x00000001
+#define FLAG_ACTIVITY_NEW_TASK 0x10000000
+
+#define FAILED(env) ((*env)->ExceptionCheck(env))
+
+// exception clears the pending Java exception and returns its description,
+// malloc'ed, or NULL when there is none.
+static char *exception(JNIEnv *env) {
+	if (!FAILED(env)) {
+		return NULL;
+	}
+	jthrowable thrown = (*env)->ExceptionOccurred(env);
+	(*env)->ExceptionClear(env);
+
+	jclass notFound = (*env)->FindClass(env, "android/content/ActivityNotFoundException");
+	if (notFound != NULL && (*env)->IsInstanceOf(env, thrown, notFound)) {
+		return strdup("no app can open this file");
+	}
+	(*env)->ExceptionClear(env);
+
+	char *message = NULL;
+	jclass throwable = (*env)->FindClass(env, "java/lang/Throwable");
+	jmethodID toString = throwable ? (*env)->GetMethodID(env, throwable, "toString", "()Ljava/lang/String;") : NULL;
+	jstring text = toString ? (*env)->CallObjectMethod(env, thrown, toString) : NULL;
+	if (text != NULL && !FAILED(env)) {
+		const char *chars = (*env)->GetStringUTFChars(env, text, NULL);
+		if (chars != NULL) {
+			message = strdup(chars);
+			(*env)->ReleaseStringUTFChars(env, text, chars);
+		}
+	}
+	(*env)->ExceptionClear(env);
+	return message ? message : strdup("java exception");
+}
+
+static jobject parse_uri(JNIEnv *env, const char *uri) {
+	jclass uriClass = (*env)->FindClass(env, "android/net/Uri");
+	if (uriClass == NULL) {
+		return NULL;
+	}
+	jmethodID parse = (*env)->GetStaticMethodID(env, uriClass, "parse", "(Ljava/lang/String;)Landroid/net/Uri;");
+	if (parse == NULL) {
+		return NULL;
+	}
+	jobject parsed = (*env)->CallStaticObjectMethod(env, uriClass, parse, (*env)->NewStringUTF(env, uri));
+	return FAILED(env) ? NULL : parsed;
+}
+
+// new_intent returns an intent viewing uri, or sending it when send is set,
+// which lets the receiving app read uri.
+static jobject new_intent(JNIEnv *env, jclass intentClass, jobject uri, const char *mime, int send) {
+	jmethodID constructor = (*env)->GetMethodID(env, intentClass, "<init>", "(Ljava/lang/String;)V");
+	if (constructor == NULL) {
+		return NULL;
+	}
+	const char *action = send ? "android.intent.action.SEND" : "android.intent.action.VIEW";
+	jobject intent = (*env)->NewObject(env, intentClass, constructor, (*env)->NewStringUTF(env, action));
+	if (FAILED(env)) {
+		return NULL;
+	}
+
+	jstring type = (*env)->NewStringUTF(env, mime);
+	if (send) {
+		jmethodID setType = (*env)->GetMethodID(env, intentClass, "setType", "(Ljava/lang/String;)Landroid/content/Intent;");
+		jmethodID putExtra = (*env)->GetMethodID(env, intentClass, "putExtra",
+			"(Ljava/lang/String;Landroid/os/Parcelable;)Landroid/content/Intent;");
+		if (setType == NULL || putExtra == NULL) {
+			return NULL;
+		}
+		(*env)->CallObjectMethod(env, intent, setType, type);
+		if (!FAILED(env)) {
+			(*env)->CallObjectMethod(env, intent, putExtra, (*env)->NewStringUTF(env, "android.intent.extra.STREAM"), uri);
+		}
+	} else {
+		jmethodID setDataAndType = (*env)->GetMethodID(env, intentClass, "setDataAndType",
+			"(Landroid/net/Uri;Ljava/lang/String;)Landroid/content/Intent;");
+		if (setDataAndType == NULL) {
+			return NULL;
+		}
+		(*env)->CallObjectMethod(env, intent, setDataAndType, uri, type);
+	}
+	return FAILED(env) ? NULL : intent;
+}
+
+static int add_flags(JNIEnv *env, jclass intentClass, jobject intent, jint flags) {
+	jmethodID addFlags = (*env)->GetMethodID(env, intentClass, "addFlags", "(I)Landroid/content/Intent;");
+	if (addFlags == NULL) {
+		return 0;
+	}
+	(*env)->CallObjectMethod(env, intent, addFlags, flags);
+	return !FAILED(env);
+}
+
+// start_intent lets the user pick an app viewing the content at uri, or
+// sending it when send is set. It returns NULL on success or a malloc'ed
+// error message.
+char *start_intent(uintptr_t jni_env, uintptr_t ctx, const char *uri, const char *mime, int send, const char *title) {
+	JNIEnv *env = (JNIEnv *)jni_env;
+	jobject context = (jobject)ctx;
+	if ((*env)->PushLocalFrame(env, 32) < 0) {
+		return exception(env);
+	}
+
+	jint flags = FLAG_GRANT_READ_URI_PERMISSION | FLAG_ACTIVITY_NEW_TASK;
+	jclass intentClass = (*env)->FindClass(env, "android/content/Intent");
+	jobject parsed = intentClass ? parse_uri(env, uri) : NULL;
+	jobject intent = parsed ? new_intent(env, intentClass, parsed, mime, send) : NULL;
+	jobject chooser = NULL;
+	if (intent != NULL && add_flags(env, intentClass, intent, flags)) {
+		jmethodID createChooser = (*env)->GetStaticMethodID(env, intentClass, "createChooser",
+			"(Landroid/content/Intent;Ljava/lang/CharSequence;)Landroid/content/Intent;");
+		if (createChooser != NULL) {
+			chooser = (*env)->CallStaticObjectMethod(env, intentClass, createChooser, intent, (*env)->NewStringUTF(env, title));
+		}
+	}
+	int started = 0;
+	if (chooser != NULL && !FAILED(env) && add_flags(env, intentClass, chooser, flags)) {
+		jclass contextClass = (*env)->GetObjectClass(env, context);
+		jmethodID startActivity = (*env)->GetMethodID(env, contextClass, "startActivity", "(Landroid/content/Intent;)V");
+		if (startActivity != NULL) {
+			(*env)->CallVoidMethod(env, context, startActivity, chooser);
+			started = !FAILED(env);
+		}
+	}
+
+	char *error = exception(env);
+	if (error == NULL && !started) {
+		error = strdup("starting the activity failed");
+	}
+	(*env)->PopLocalFrame(env, NULL);
+	return error;
+}
@@ -0,0 +1,57 @@
+//go:build android && !ci
+
+package main
+
+/*
+#include <stdlib.h>
+
+#include "share_android.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver"
+)
+
+// privateStorage is set when other apps cannot read the app storage, so
+// that files must be exported before being opened or shared.
+const privateStorage = true
+
+// openURI lets the user pick an app opening the file at u.
+func openURI(_ fyne.App, u fyne.URI, mimeType string) error {
+	return startIntent(u, mimeType, false, "Open with")
+}
+
+// shareURI lets the user pick an app the file at u is sent to.
+func shareURI(u fyne.URI, mimeType string) error {
+	return startIntent(u, mimeType, true, "Share")
+}
+
+func startIntent(u fyne.URI, mimeType string, send bool, title string) error {
+	uri := C.CString(u.String())
+	defer C.free(unsafe.Pointer(uri))
+	mime := C.CString(mimeType)
+	defer C.free(unsafe.Pointer(mime))
+	chooser := C.CString(title)
+	defer C.free(unsafe.Pointer(chooser))
+	sending := C.int(0)
+	if send {
+		sending = 1
+	}
+
+	return driver.RunNative(func(ctx any) error {
+		android, ok := ctx.(*driver.AndroidContext)
+		if !ok {
+			return errors.New("no Android context")
+		}
+		if message := C.start_intent(C.uintptr_t(android.Env), C.uintptr_t(android.Ctx), uri, mime, sending, chooser); message != nil {
+			defer C.free(unsafe.Pointer(message))
+			return errors.New(C.GoString(message))
+		}
+		return nil
+	})
+}
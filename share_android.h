@@ -0,0 +1,3 @@
+#include <stdint.h>
+
+char *start_intent(uintptr_t jni_env, uintptr_t ctx, const char *uri, const char *mime, int send, const char *title);
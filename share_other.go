@@ -0,0 +1,28 @@
+//go:build !android || ci
+
+package main
+
+import (
+	"errors"
+	"net/url"
+
+	"fyne.io/fyne/v2"
+)
+
+// privateStorage is set when other apps cannot read the app storage, so
+// that files must be exported before being opened or shared.
+const privateStorage = false
+
+// openURI opens the file at u with the default app of the system.
+func openURI(a fyne.App, u fyne.URI, _ string) error {
+	link, err := url.Parse(u.String())
+	if err != nil {
+		return err
+	}
+	return a.OpenURL(link)
+}
+
+// shareURI is only supported on Android.
+func shareURI(fyne.URI, string) error {
+	return errors.New("sharing is not supported on this platform")
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"webservices/jenkins"
+)
+
+const (
+	testsFailed      = "Failed"
+	testsNewFailures = "New failures"
+	testsFixed       = "Fixed"
+	testsAll         = "All tests"
+
+	// testHistorySize is how many earlier builds the history of a test spans.
+	testHistorySize = 10
+)
+
+var testFilters = []string{testsFailed, testsNewFailures, testsFixed, testsAll}
+
+// testReportViewer shows the JUnit results of a build compared with the
+// previous build.
+type testReportViewer struct {
+	ctx    context.Context
+	window fyne.Window
+	client *jenkins.Client
+	build  jenkins.Build
+
+	report *jenkins.TestReport
+	diff   jenkins.TestDiff
+	// earlier are the finished builds before this one, the most recent
+	// first, and reports caches the results fetched for them.
+	earlier []jenkins.Build
+	reports map[int]*jenkins.TestReport
+
+	filter  string
+	cases   []jenkins.TestCase
+	shown   []jenkins.TestCase
+	list    *widget.List
+	summary *widget.Label
+}
+
+// showTestReport opens the test results of a build in a new window.
+func showTestReport(ctx context.Context, a fyne.App, client *jenkins.Client, title string, build jenkins.Build) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	w := a.NewWindow("Tests: " + title)
+	w.SetOnClosed(cancel)
+
+	v := &testReportViewer{
+		ctx:     ctx,
+		window:  w,
+		client:  client,
+		build:   build,
+		reports: map[int]*jenkins.TestReport{},
+		filter:  testsFailed,
+		summary: widget.NewLabel("Loading test report..."),
+	}
+	v.summary.Wrapping = fyne.TextWrapWord
+
+	v.list = widget.NewList(
+		func() int {
+			return len(v.shown)
+		},
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("")
+			name.Truncation = fyne.TextTruncateEllipsis
+			class := widget.NewLabel("")
+			class.SizeName = theme.SizeNameCaptionText
+			class.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, widget.NewIcon(nil), nil, container.NewVBox(name, class))
+		},
+		func(id widget.ListItemID, o fyne.CanvasObject) {
+			test := v.shown[id]
+			row := o.(*fyne.Container)
+			row.Objects[1].(*widget.Icon).SetResource(testIcon(test.Status))
+			lines := row.Objects[0].(*fyne.Container).Objects
+			lines[0].(*widget.Label).SetText(test.Name)
+			lines[1].(*widget.Label).SetText(test.ClassName)
+		},
+	)
+	v.list.OnSelected = func(id widget.ListItemID) {
+		v.list.Unselect(id)
+		v.showTest(v.shown[id])
+	}
+
+	filter := widget.NewSelect(testFilters, func(filter string) {
+		v.filter = filter
+		v.applyFilter()
+	})
+	filter.SetSelected(v.filter)
+
+	w.SetContent(container.NewBorder(container.NewVBox(v.summary, filter), nil, nil, nil, v.list))
+	w.Resize(fyne.NewSize(600, 700))
+	w.Show()
+
+	go v.load()
+}
+
+// load fetches the report of the build and of the closest earlier build
+// which has one.
+func (v *testReportViewer) load() {
+	report, err := v.client.TestReport(v.ctx, v.build.URL)
+	if err != nil {
+		fyne.Do(func() {
+			if errors.Is(err, jenkins.ErrNotFound) {
+				v.summary.SetText("This build has no test report")
+			} else {
+				v.summary.SetText("Error fetching test report: " + err.Error())
+			}
+		})
+		return
+	}
+
+	history, err := v.client.BuildHistory(v.ctx, v.build.JobURL(), 0, 50)
+	if err != nil {
+		fmt.Println("Error fetching builds for the test history: " + err.Error())
+	}
+	var earlier []jenkins.Build
+	for _, build := range history {
+		if build.Number < v.build.Number && !build.Building && len(earlier) < testHistorySize {
+			earlier = append(earlier, build)
+		}
+	}
+
+	var previous *jenkins.Build
+	var previousReport *jenkins.TestReport
+	for _, build := range earlier {
+		if statuses, err := v.client.TestStatuses(v.ctx, build.URL); err == nil {
+			previous, previousReport = &build, statuses
+			break
+		}
+	}
+
+	fyne.Do(func() {
+		v.report = report
+		v.earlier = earlier
+		v.cases = slices.Collect(maps.Values(report.Cases()))
+		slices.SortFunc(v.cases, func(a, b jenkins.TestCase) int {
+			return cmp.Or(cmp.Compare(testRank(a), testRank(b)), cmp.Compare(a.ID(), b.ID()))
+		})
+
+		summary := fmt.Sprintf("%d passed, %d failed, %d skipped", report.PassCount, report.FailCount, report.SkipCount)
+		if previous != nil {
+			v.reports[previous.Number] = previousReport
+			v.diff = report.Diff(previousReport)
+			summary += fmt.Sprintf("\n%d new failures and %d fixed since #%d",
+				len(v.diff.NewFailures), len(v.diff.Fixed), previous.Number)
+		} else {
+			summary += "\nNo earlier test report to compare with"
+		}
+		v.summary.SetText(summary)
+		v.applyFilter()
+	})
+}
+
+func (v *testReportViewer) applyFilter() {
+	v.shown = nil
+	for _, test := range v.cases {
+		var show bool
+		switch v.filter {
+		case testsFailed:
+			show = test.Failed()
+		case testsNewFailures:
+			show = slices.Contains(v.diff.NewFailures, test.ID())
+		case testsFixed:
+			show = slices.Contains(v.diff.Fixed, test.ID())
+		default:
+			show = true
+		}
+		if show {
+			v.shown = append(v.shown, test)
+		}
+	}
+	v.list.Refresh()
+}
+
+// showTest shows the output of a test and its results in earlier builds.
+func (v *testReportViewer) showTest(test jenkins.TestCase) {
+	details := widget.NewLabel(fmt.Sprintf("%s in %.2fs", test.Status, test.Duration))
+	if test.FailedSince > 0 {
+		details.SetText(details.Text + fmt.Sprintf(", failing since #%d", test.FailedSince))
+	}
+
+	output := strings.TrimSpace(test.ErrorDetails + "\n\n" + test.ErrorStackTrace)
+	if output == "" {
+		output = "No output"
+	}
+	trace := widget.NewTextGridFromString(output)
+
+	history := container.NewVBox(widget.NewLabel("Loading history..."))
+	content := container.NewBorder(
+		container.NewVBox(details, history, widget.NewSeparator()), nil, nil, nil,
+		container.NewScroll(trace),
+	)
+
+	info := dialog.NewCustom(test.Name, "Close", content, v.window)
+	info.Resize(fyne.NewSize(560, 500))
+	info.Show()
+
+	go v.loadHistory(test, history)
+}
+
+// loadHistory plots the status of a test in the earlier builds, oldest
+// first, fetching the reports not cached yet.
+func (v *testReportViewer) loadHistory(test jenkins.TestCase, history *fyne.Container) {
+	statuses := []string{test.Status}
+	numbers := []int{v.build.Number}
+	for _, build := range v.earlier {
+		var report *jenkins.TestReport
+		fyne.DoAndWait(func() { report = v.reports[build.Number] })
+		if report == nil {
+			var err error
+			report, err = v.client.TestStatuses(v.ctx, build.URL)
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			if err != nil {
+				report = &jenkins.TestReport{}
+			}
+			fyne.Do(func() { v.reports[build.Number] = report })
+		}
+
+		status := ""
+		if earlier, ok := report.Cases()[test.ID()]; ok {
+			status = earlier.Status
+		}
+		statuses = append(statuses, status)
+		numbers = append(numbers, build.Number)
+	}
+	slices.Reverse(statuses)
+	slices.Reverse(numbers)
+
+	ones := make([]float64, len(statuses))
+	colors := make([]fyne.ThemeColorName, len(statuses))
+	var lines []string
+	for i, status := range statuses {
+		ones[i] = 1
+		colors[i] = testColor(status)
+		if status == "" {
+			status = "not run"
+		}
+		lines = append(lines, fmt.Sprintf("#%d %s", numbers[i], status))
+	}
+
+	fyne.Do(func() {
+		caption := widget.NewLabel(strings.Join(lines, " · "))
+		caption.SizeName = theme.SizeNameCaptionText
+		caption.Wrapping = fyne.TextWrapWord
+		history.Objects = []fyne.CanvasObject{newBarChart(ones, colors, nil), caption}
+		history.Refresh()
+	})
+}
+
+// testRank lists failures first, then skipped and passed tests.
+func testRank(test jenkins.TestCase) int {
+	switch {
+	case test.Failed():
+		return 0
+	case test.Skipped():
+		return 1
+	}
+	return 2
+}
+
+func testIcon(status string) fyne.Resource {
+	switch status {
+	case jenkins.TestFailed, jenkins.TestRegression:
+		return theme.NewErrorThemedResource(theme.CancelIcon())
+	case jenkins.TestSkipped:
+		return theme.NewDisabledResource(theme.MediaSkipNextIcon())
+	}
+	return theme.NewSuccessThemedResource(theme.ConfirmIcon())
+}
+
+func testColor(status string) fyne.ThemeColorName {
+	switch status {
+	case jenkins.TestPassed, jenkins.TestFixed:
+		return theme.ColorNameSuccess
+	case jenkins.TestFailed, jenkins.TestRegression:
+		return theme.ColorNameError
+	case jenkins.TestSkipped:
+		return theme.ColorNameDisabled
+	}
+	return theme.ColorNamePlaceHolder
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"webservices/jenkins"
+)
+
+// trendSizes are the numbers of recent builds the trends can span.
+var trendSizes = []string{"10", "25", "50", "100"}
+
+// buildTrends charts the duration, time in queue and results of the recent
+// builds of a job.
+type buildTrends struct {
+	ctx    context.Context
+	client *jenkins.Client
+	jobURL string
+
+	size    *widget.Select
+	status  *widget.Label
+	charts  *fyne.Container
+	content fyne.CanvasObject
+}
+
+func newBuildTrends(ctx context.Context, client *jenkins.Client, jobURL string) *buildTrends {
+	t := &buildTrends{
+		ctx:    ctx,
+		client: client,
+		jobURL: jobURL,
+		status: widget.NewLabel(""),
+		charts: container.NewVBox(),
+	}
+	t.size = widget.NewSelect(trendSizes, nil)
+	t.size.SetSelectedIndex(1)
+	t.size.OnChanged = func(string) { t.load() }
+
+	t.content = container.NewBorder(
+		container.NewHBox(widget.NewLabel("Last"), t.size, widget.NewLabel("builds")), t.status, nil, nil,
+		container.NewVScroll(t.charts),
+	)
+	return t
+}
+
+// load fetches the builds and plots them, the running ones aside.
+func (t *buildTrends) load() {
+	size, _ := strconv.Atoi(t.size.Selected)
+	t.status.SetText("Loading builds...")
+
+	go func() {
+		builds, err := t.client.BuildHistory(t.ctx, t.jobURL, 0, size)
+		fyne.Do(func() {
+			if err != nil {
+				t.status.SetText("Error fetching builds: " + err.Error())
+				return
+			}
+			if selected, _ := strconv.Atoi(t.size.Selected); selected != size {
+				return
+			}
+
+			builds = slices.DeleteFunc(builds, func(build jenkins.Build) bool { return build.Building })
+			slices.Reverse(builds)
+			t.plot(builds)
+			t.status.SetText(fmt.Sprintf("%d finished builds, oldest on the left", len(builds)))
+		})
+	}()
+}
+
+func (t *buildTrends) plot(builds []jenkins.Build) {
+	t.charts.RemoveAll()
+	if len(builds) == 0 {
+		t.charts.Add(widget.NewLabel("No finished builds yet"))
+		return
+	}
+
+	colors := make([]fyne.ThemeColorName, len(builds))
+	durations := make([]float64, len(builds))
+	ones := make([]float64, len(builds))
+	var queued []float64
+	passed := 0
+	for i, build := range builds {
+		colors[i] = resultColor(build)
+		durations[i] = float64(build.Duration)
+		ones[i] = 1
+		if build.Status() == jenkins.StatusSuccess {
+			passed++
+		}
+		if queue, ok := build.QueueTime(); ok {
+			queued = append(queued, float64(queue.Milliseconds()))
+		}
+	}
+
+	last := builds[len(builds)-1]
+	t.addChart("Duration",
+		fmt.Sprintf("Last build took %s", last.Elapsed().Round(time.Second)),
+		newBarChart(durations, colors, formatMillis),
+	)
+
+	if len(queued) == len(builds) {
+		primary := make([]fyne.ThemeColorName, len(queued))
+		for i := range primary {
+			primary[i] = theme.ColorNamePrimary
+		}
+		lastQueue, _ := last.QueueTime()
+		t.addChart("Time in queue",
+			fmt.Sprintf("Last build waited %s", lastQueue.Round(time.Second)),
+			newBarChart(queued, primary, formatMillis),
+		)
+	} else {
+		t.addChart("Time in queue", "Jenkins reports it with the Metrics plugin installed", nil)
+	}
+
+	t.addChart("Results",
+		fmt.Sprintf("%d%% passed, %d of %d builds", passed*100/len(builds), passed, len(builds)),
+		newBarChart(ones, colors, nil),
+	)
+}
+
+func (t *buildTrends) addChart(title, summary string, chart fyne.CanvasObject) {
+	heading := widget.NewLabel(title)
+	heading.TextStyle.Bold = true
+	caption := widget.NewLabel(summary)
+	caption.SizeName = theme.SizeNameCaptionText
+
+	t.charts.Add(heading)
+	t.charts.Add(caption)
+	if chart != nil {
+		t.charts.Add(chart)
+	}
+	t.charts.Add(widget.NewSeparator())
+}
+
+func resultColor(build jenkins.Build) fyne.ThemeColorName {
+	switch build.Status() {
+	case jenkins.StatusSuccess:
+		return theme.ColorNameSuccess
+	case jenkins.StatusFailed:
+		return theme.ColorNameError
+	case jenkins.StatusUnstable:
+		return theme.ColorNameWarning
+	}
+	return theme.ColorNameDisabled
+}
+
+func formatMillis(millis float64) string {
+	return (time.Duration(millis) * time.Millisecond).Round(time.Second).String()
+}